@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	AMAZON_NAME                 = "Amazon Appstore"
+	AMAZON_DEFAULT_MARKETPLACE  = "amazon.com"
+	AMAZON_MAX_PAGES            = 10
+	AMAZON_USER_AGENT           = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15"
+	AMAZON_REVIEW_CLASS_NAME    = `div[data-hook="review"]`
+	AMAZON_AUTHOR_CLASS_NAME    = ".a-profile-name"
+	AMAZON_RATING_CLASS_NAME    = `i[data-hook="review-star-rating"], i[data-hook="cmps-review-star-rating"]`
+	AMAZON_TITLE_CLASS_NAME     = `[data-hook="review-title"]`
+	AMAZON_DATE_CLASS_NAME      = `span[data-hook="review-date"]`
+	AMAZON_BODY_CLASS_NAME      = `span[data-hook="review-body"]`
+	AMAZON_NEXT_PAGE_CLASS_NAME = "li.a-last a"
+)
+
+var (
+	amazonClient        = &http.Client{Timeout: 30 * time.Second}
+	amazonStarClass     = regexp.MustCompile(`a-star-(\d)`)
+	amazonDecimalRating = regexp.MustCompile(`(\d)[.,]\d`)
+)
+
+// AmazonMarketplace describes a marketplace domain, dates of review pages
+// are written in its locale.
+type AmazonMarketplace struct {
+	Country string
+	Locale  string
+}
+
+// AMAZON_MARKETPLACES lists supported marketplace domains.
+var AMAZON_MARKETPLACES = map[string]AmazonMarketplace{
+	"amazon.com":    {"us", "en_US"},
+	"amazon.ca":     {"ca", "en_CA"},
+	"amazon.co.uk":  {"gb", "en_GB"},
+	"amazon.com.au": {"au", "en_AU"},
+	"amazon.in":     {"in", "en_IN"},
+	"amazon.de":     {"de", "de_DE"},
+	"amazon.fr":     {"fr", "fr_FR"},
+	"amazon.it":     {"it", "it_IT"},
+	"amazon.es":     {"es", "es_ES"},
+	"amazon.co.jp":  {"jp", "ja_JP"},
+}
+
+// ParseDate reads the date of a review date line such as "Reviewed in the
+// United States on January 2, 2023".
+func (m AmazonMarketplace) ParseDate(text string) (time.Time, error) {
+	return ParseLocalDate(m.Locale, text, time.UTC)
+}
+
+func init() {
+	RegisterSource(AMAZON_NAME, NewAmazonSource)
+}
+
+type AmazonSource struct {
+	config      AppConfig
+	domain      string
+	marketplace AmazonMarketplace
+}
+
+func NewAmazonSource(config AppConfig) ReviewSource {
+	if config.AmazonASIN == "" {
+		return nil
+	}
+
+	domain := strings.TrimPrefix(strings.ToLower(config.AmazonMarketplace), "www.")
+	if domain == "" {
+		domain = AMAZON_DEFAULT_MARKETPLACE
+	}
+	marketplace, ok := AMAZON_MARKETPLACES[domain]
+	if !ok {
+		log.Printf("Amazon marketplace %s of %s is not supported", domain, config.Key())
+		return nil
+	}
+
+	return &AmazonSource{config, domain, marketplace}
+}
+
+func (s *AmazonSource) Name() string {
+	return AMAZON_NAME
+}
+
+func (s *AmazonSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true, Countries: true}
+}
+
+// Fetch walks the most recent first review pages until a known review or
+// one older than since is reached.
+func (s *AmazonSource) Fetch(since time.Time) (Reviews, error) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for page := 1; page <= MaxPages(AMAZON_MAX_PAGES); page++ {
+		pageReviews, pageErrors, hasNext, err := GetAmazonReviews(s.config, s.domain, s.marketplace, page)
+		if err != nil {
+			return nil, err
+		}
+		parseErrors = append(parseErrors, pageErrors...)
+
+		unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, unseen...)
+		if done || !hasNext {
+			break
+		}
+	}
+
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
+
+// GetAmazonReviews returns reviews of a customer reviews page of the product,
+// and whether a next page exists.
+func GetAmazonReviews(config AppConfig, domain string, marketplace AmazonMarketplace, page int) (Reviews, ParseErrors, bool, error) {
+	query := url.Values{}
+	query.Add("sortBy", "recent")
+	query.Add("reviewerType", "all_reviews")
+	query.Add("pageNumber", strconv.Itoa(page))
+	uri := fmt.Sprintf("https://www.%s/product-reviews/%s?%s", domain, url.PathEscape(config.AmazonASIN), query.Encode())
+	log.Println(uri)
+
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	req.Header.Set("User-Agent", AMAZON_USER_AGENT)
+
+	res, err := amazonClient.Do(req)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, false, fmt.Errorf("%s responded %s", uri, res.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	reviews, parseErrors := ParseAmazonReviews(doc, domain, marketplace)
+	for _, parseError := range parseErrors {
+		parseError.Page = page
+	}
+
+	hasNext := doc.Find(AMAZON_NEXT_PAGE_CLASS_NAME).Length() > 0
+	return reviews, parseErrors, hasNext, nil
+}
+
+// ParseAmazonReviews maps the reviews of a customer reviews page.
+func ParseAmazonReviews(doc *goquery.Document, domain string, marketplace AmazonMarketplace) (Reviews, ParseErrors) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+
+	doc.Find(AMAZON_REVIEW_CLASS_NAME).Each(func(i int, s *goquery.Selection) {
+		review, err := parseAmazonReview(s, domain, marketplace)
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{
+				Source:  AMAZON_NAME,
+				Country: marketplace.Country,
+				Entry:   i,
+				Err:     err,
+			})
+			return
+		}
+		reviews = append(reviews, review)
+	})
+
+	sort.Sort(reviews)
+	return reviews, parseErrors
+}
+
+func parseAmazonReview(s *goquery.Selection, domain string, marketplace AmazonMarketplace) (Review, error) {
+	id, _ := s.Attr("id")
+	if id == "" {
+		return Review{}, fmt.Errorf("missing review id")
+	}
+
+	rate := parseAmazonRate(s.Find(AMAZON_RATING_CLASS_NAME).First())
+	if rate < 1 || rate > 5 {
+		return Review{}, fmt.Errorf("invalid rating of review %s", id)
+	}
+
+	dateText := strings.TrimSpace(s.Find(AMAZON_DATE_CLASS_NAME).First().Text())
+	date, err := marketplace.ParseDate(dateText)
+	if err != nil {
+		return Review{}, fmt.Errorf("review %s: %v", id, err)
+	}
+
+	// the title link also holds the rating text on recent pages
+	titleNode := s.Find(AMAZON_TITLE_CLASS_NAME).First()
+	titleNode.Find(".a-icon-alt").Remove()
+	title := strings.TrimSpace(titleNode.Text())
+	if len(title) == 0 {
+		title = "No title provided"
+	}
+
+	return Review{
+		ExternalID: id,
+		Author:     strings.TrimSpace(s.Find(AMAZON_AUTHOR_CLASS_NAME).First().Text()),
+		Store:      AMAZON_NAME,
+		Country:    marketplace.Country,
+		Title:      title,
+		Message:    strings.TrimSpace(s.Find(AMAZON_BODY_CLASS_NAME).First().Text()),
+		Rating:     rate,
+		Rate:       parseAppStoreRate(rate),
+		UpdatedAt:  date,
+		Permalink:  fmt.Sprintf("https://www.%s/gp/customer-reviews/%s", domain, id),
+	}, nil
+}
+
+// parseAmazonRate reads the star count from the a-star-N class of the rating
+// icon, falling back to the decimal rating of its text such as "4.0 out of 5
+// stars" or "5つ星のうち4.0".
+func parseAmazonRate(s *goquery.Selection) int {
+	class, _ := s.Attr("class")
+	if match := amazonStarClass.FindStringSubmatch(class); match != nil {
+		rate, _ := strconv.Atoi(match[1])
+		return rate
+	}
+
+	if match := amazonDecimalRating.FindStringSubmatch(s.Text()); match != nil {
+		rate, _ := strconv.Atoi(match[1])
+		return rate
+	}
+
+	return 0
+}
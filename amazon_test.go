@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func loadAmazonFixture(t *testing.T, name string) *goquery.Document {
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestParseAmazonReviews(t *testing.T) {
+	for _, test := range []struct {
+		fixture  string
+		domain   string
+		hasNext  bool
+		skipped  int
+		expected Reviews
+	}{
+		{
+			fixture: "amazon_us.html",
+			domain:  "amazon.com",
+			hasNext: true,
+			skipped: 1,
+			expected: Reviews{
+				{
+					ExternalID: "R2C3LXSD8H3P1Q",
+					Author:     "Jane Doe",
+					Country:    "us",
+					Title:      "Works well on my Fire tablet",
+					Message:    "Sync is quick and the widgets are handy. Dark mode would be nice.",
+					Rating:     4,
+					UpdatedAt:  time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+					Permalink:  "https://www.amazon.com/gp/customer-reviews/R2C3LXSD8H3P1Q",
+				},
+				{
+					ExternalID: "R1XK9PQ2M7ZB4T",
+					Author:     "Amazon Customer",
+					Country:    "us",
+					Title:      "Crashes on launch",
+					Message:    "Since the last update it closes right after the splash screen.",
+					Rating:     1,
+					UpdatedAt:  time.Date(2022, 12, 28, 0, 0, 0, 0, time.UTC),
+					Permalink:  "https://www.amazon.com/gp/customer-reviews/R1XK9PQ2M7ZB4T",
+				},
+			},
+		},
+		{
+			fixture: "amazon_de.html",
+			domain:  "amazon.de",
+			expected: Reviews{
+				{
+					ExternalID: "R7DE4QK2W9VX1L",
+					Author:     "Max Mustermann",
+					Country:    "de",
+					Title:      "Benachrichtigungen kommen zu spät",
+					Message:    "Die Erinnerungen erscheinen oft erst Stunden später.",
+					Rating:     2,
+					UpdatedAt:  time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC),
+					Permalink:  "https://www.amazon.de/gp/customer-reviews/R7DE4QK2W9VX1L",
+				},
+			},
+		},
+	} {
+		doc := loadAmazonFixture(t, test.fixture)
+		reviews, parseErrors := ParseAmazonReviews(doc, test.domain, AMAZON_MARKETPLACES[test.domain])
+
+		if len(parseErrors) != test.skipped {
+			t.Errorf("%s: %d entries skipped, want %d: %v", test.fixture, len(parseErrors), test.skipped, parseErrors)
+		}
+		if hasNext := doc.Find(AMAZON_NEXT_PAGE_CLASS_NAME).Length() > 0; hasNext != test.hasNext {
+			t.Errorf("%s: next page %v, want %v", test.fixture, hasNext, test.hasNext)
+		}
+		if len(reviews) != len(test.expected) {
+			t.Fatalf("%s: %d reviews, want %d", test.fixture, len(reviews), len(test.expected))
+		}
+
+		for i, want := range test.expected {
+			got := reviews[i]
+			if got.ExternalID != want.ExternalID || got.Author != want.Author || got.Country != want.Country ||
+				got.Title != want.Title || got.Message != want.Message || got.Rating != want.Rating ||
+				!got.UpdatedAt.Equal(want.UpdatedAt) || got.Permalink != want.Permalink {
+				t.Errorf("%s: review %d is %+v, want %+v", test.fixture, i, got, want)
+			}
+			if got.Store != AMAZON_NAME || got.Rate != parseAppStoreRate(want.Rating) {
+				t.Errorf("%s: review %d has store %q and rate %q", test.fixture, i, got.Store, got.Rate)
+			}
+		}
+	}
+}
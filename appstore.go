@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	APP_STORE_NAME     = "App Store"
+	APP_STORE_BASE_URI = "https://itunes.apple.com"
+	// APP_STORE_REVIEWS_URI lists the reviews of an app in a storefront, by
+	// country and app id. The feed has no link to a single review.
+	APP_STORE_REVIEWS_URI = "https://apps.apple.com/%s/app/id%s?see-all=reviews"
+)
+
+var appStoreClient = &http.Client{Timeout: 30 * time.Second}
+
+func init() {
+	RegisterSource(APP_STORE_NAME, NewAppStoreSource)
+}
+
+type AppStoreSource struct {
+	config AppConfig
+}
+
+func NewAppStoreSource(config AppConfig) ReviewSource {
+	if config.AppStoreURI == "" {
+		return nil
+	}
+	// the App Store Connect API source takes over when an API key is configured
+	if config.AppStoreConnectKeyId != "" && config.AppStoreConnectIssuerId != "" && config.AppStoreConnectKey != "" {
+		return nil
+	}
+	return &AppStoreSource{config}
+}
+
+func (s *AppStoreSource) Name() string {
+	return APP_STORE_NAME
+}
+
+func (s *AppStoreSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true, Countries: true}
+}
+
+// appStoreRatings reads the overall rating of every watched storefront
+// through the iTunes lookup API, as many at a time as reviews are fetched.
+func appStoreRatings(config AppConfig) ([]RatingSummary, error) {
+	storefronts := config.AppStoreStorefronts()
+	listings := make([]AppMetadata, len(storefronts))
+	errs := make([]error, len(storefronts))
+	config.EachStorefront(storefronts, func(i int, country string) {
+		listings[i], errs[i] = GetAppStoreMetadata(config, country)
+	})
+
+	summaries := []RatingSummary{}
+	for i, country := range storefronts {
+		if errs[i] != nil {
+			// apps are not available in every storefront
+			log.Printf("App Store rating of %s in %s: %v", config.Key(), country, errs[i])
+			continue
+		}
+		summaries = append(summaries, RatingSummary{
+			Store:   APP_STORE_NAME,
+			Country: country,
+			Average: listings[i].AverageRating,
+			Count:   listings[i].RatingCount,
+		})
+	}
+	return summaries, nil
+}
+
+func (s *AppStoreSource) RatingSummaries() ([]RatingSummary, error) {
+	return appStoreRatings(s.config)
+}
+
+// Fetch walks every configured storefront, at most AppStoreConcurrency of
+// them at a time. Storefronts failing to respond are logged and skipped,
+// malformed entries are reported with ParseErrors.
+func (s *AppStoreSource) Fetch(since time.Time) (Reviews, error) {
+	storefronts := s.config.AppStoreStorefronts()
+	results := make([]Reviews, len(storefronts))
+	parseErrors := make([]ParseErrors, len(storefronts))
+	errs := make([]error, len(storefronts))
+
+	s.config.EachStorefront(storefronts, func(i int, country string) {
+		results[i], parseErrors[i], errs[i] = s.fetchStorefront(country, since)
+	})
+
+	reviews := Reviews{}
+	skipped := ParseErrors{}
+	var err error
+	for i, country := range storefronts {
+		skipped = append(skipped, parseErrors[i]...)
+		if errs[i] != nil {
+			log.Printf("App Store %s: %v", country, errs[i])
+			if err == nil {
+				err = errs[i]
+			}
+			continue
+		}
+		reviews = append(reviews, results[i]...)
+	}
+
+	if len(reviews) == 0 && err != nil {
+		return nil, err
+	}
+
+	sort.Sort(reviews)
+	if len(skipped) > 0 {
+		return reviews, skipped
+	}
+	return reviews, nil
+}
+
+// EachStorefront calls fn with every storefront and its index, at most
+// AppStoreConcurrency of them at a time, and returns once all are done.
+func (app AppConfig) EachStorefront(storefronts []string, fn func(i int, country string)) {
+	limit := make(chan struct{}, app.AppStoreConcurrency)
+	var wg sync.WaitGroup
+	for i, country := range storefronts {
+		wg.Add(1)
+		go func(i int, country string) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			fn(i, country)
+		}(i, country)
+	}
+	wg.Wait()
+}
+
+// fetchStorefront walks the pages of a single storefront until a known review
+// or one older than the storefront watermark is reached. Storefronts lag
+// independently so the watermark of the whole store is not used, except by
+// backfills which walk every storefront back to the same date.
+func (s *AppStoreSource) fetchStorefront(country string, since time.Time) (Reviews, ParseErrors, error) {
+	if !backfilling {
+		var err error
+		since, err = dbh.Watermark(s.config.Key(), APP_STORE_NAME, country)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for page := 1; page <= APP_STORE_MAX_PAGES; page++ {
+		pageReviews, pageErrors, err := GetAppStoreReviews(s.config, country, page)
+		if err != nil {
+			return nil, nil, err
+		}
+		parseErrors = append(parseErrors, pageErrors...)
+		if len(pageReviews) == 0 {
+			break
+		}
+
+		unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+		if err != nil {
+			return nil, nil, err
+		}
+		reviews = append(reviews, unseen...)
+		if done {
+			break
+		}
+	}
+
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors, nil
+	}
+	return reviews, nil, nil
+}
+
+type appStoreLabel struct {
+	Label string `json:"label"`
+}
+
+type appStoreFeed struct {
+	Feed struct {
+		Entry appStoreEntries `json:"entry"`
+	} `json:"feed"`
+}
+
+// appStoreEntries keeps entries undecoded so a malformed one can be skipped,
+// the feed renders a single entry as an object instead of an array.
+type appStoreEntries []json.RawMessage
+
+func (e *appStoreEntries) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		*e = appStoreEntries{json.RawMessage(data)}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]json.RawMessage)(e))
+}
+
+type appStoreEntry struct {
+	Id     appStoreLabel `json:"id"`
+	Author struct {
+		Name appStoreLabel `json:"name"`
+		Uri  appStoreLabel `json:"uri"`
+	} `json:"author"`
+	Updated appStoreLabel `json:"updated"`
+	// Rating is missing on the entry describing the app itself.
+	Rating  *appStoreLabel `json:"im:rating"`
+	Version appStoreLabel  `json:"im:version"`
+	Title   appStoreLabel  `json:"title"`
+	Content appStoreLabel  `json:"content"`
+}
+
+// GetAppStoreReviews returns reviews of a page of the customer reviews feed
+// of a storefront. Malformed entries are skipped and reported in ParseErrors.
+func GetAppStoreReviews(config AppConfig, country string, page int) (Reviews, ParseErrors, error) {
+	feedUri := fmt.Sprintf("%s/%s/rss/customerreviews/page=%d/id=%s/sortby=mostrecent/json", APP_STORE_BASE_URI, country, page, config.AppStoreAppId)
+	log.Println(feedUri)
+
+	response, err := appStoreClient.Get(feedUri)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%s responded %s", feedUri, response.Status)
+	}
+
+	var feed appStoreFeed
+	if err := json.NewDecoder(response.Body).Decode(&feed); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s failed: %v", feedUri, err)
+	}
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for i, data := range feed.Feed.Entry {
+		review, ok, err := parseAppStoreEntry(data)
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{
+				Source:  APP_STORE_NAME,
+				Country: country,
+				Page:    page,
+				Entry:   i,
+				Err:     err,
+			})
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		review.Country = country
+		review.Permalink = fmt.Sprintf(APP_STORE_REVIEWS_URI, country, config.AppStoreAppId)
+		reviews = append(reviews, review)
+	}
+
+	sort.Sort(reviews)
+	return reviews, parseErrors, nil
+}
+
+// parseAppStoreEntry decodes a feed entry, ok is false for entries which are not reviews.
+func parseAppStoreEntry(data json.RawMessage) (review Review, ok bool, err error) {
+	var entry appStoreEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return review, false, err
+	}
+
+	if entry.Rating == nil {
+		return review, false, nil
+	}
+
+	rate, err := strconv.Atoi(entry.Rating.Label)
+	if err != nil || rate < 1 || rate > 5 {
+		return review, false, fmt.Errorf("invalid rating %q", entry.Rating.Label)
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339, entry.Updated.Label)
+	if err != nil {
+		return review, false, fmt.Errorf("invalid updated date %q", entry.Updated.Label)
+	}
+
+	if entry.Id.Label == "" {
+		return review, false, fmt.Errorf("missing review id")
+	}
+
+	review = Review{
+		ExternalID: entry.Id.Label,
+		Author:     entry.Author.Name.Label,
+		Store:      APP_STORE_NAME,
+		Title:      entry.Title.Label,
+		Message:    entry.Content.Label,
+		Rating:     rate,
+		Rate:       parseAppStoreRate(rate),
+		AppVersion: entry.Version.Label,
+		UpdatedAt:  updatedAt,
+		AuthorLink: entry.Author.Uri.Label,
+	}
+
+	return review, true, nil
+}
+
+func parseAppStoreRate(count int) string {
+	rateMessage := ""
+	if count < 5 {
+		rateMessage = strings.Repeat(RATING_EMOJI, count)
+	} else {
+		rateMessage = strings.Repeat(RATING_EMOJI_2, count)
+	}
+
+	return rateMessage
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	APP_STORE_CONNECT_SOURCE_NAME = "App Store Connect API"
+	APP_STORE_CONNECT_BASE_URI    = "https://api.appstoreconnect.apple.com"
+	APP_STORE_CONNECT_AUDIENCE    = "appstoreconnect-v1"
+	APP_STORE_CONNECT_PAGE_SIZE   = 200
+	APP_STORE_CONNECT_MAX_PAGES   = 50
+	// tokens live at most 20 minutes
+	APP_STORE_CONNECT_TOKEN_TTL = 15 * time.Minute
+)
+
+func init() {
+	RegisterSource(APP_STORE_CONNECT_SOURCE_NAME, NewAppStoreConnectSource)
+}
+
+// AppStoreConnectClient calls the App Store Connect API with tokens signed
+// by an API key.
+type AppStoreConnectClient struct {
+	baseURI  string
+	keyId    string
+	issuerId string
+	key      *ecdsa.PrivateKey
+	client   *http.Client
+}
+
+// NewAppStoreConnectClient returns a client for the API key configured for
+// config, or nil when none is configured.
+func NewAppStoreConnectClient(config AppConfig) (*AppStoreConnectClient, error) {
+	if config.AppStoreConnectKeyId == "" || config.AppStoreConnectIssuerId == "" || config.AppStoreConnectKey == "" {
+		return nil, nil
+	}
+
+	// the key is given either as the .p8 file path or as its content
+	data := []byte(config.AppStoreConnectKey)
+	if !strings.Contains(config.AppStoreConnectKey, "-----BEGIN") {
+		var err error
+		data, err = ioutil.ReadFile(config.AppStoreConnectKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parsed, err := ParsePKCS8PrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("App Store Connect key is not an ECDSA key")
+	}
+
+	return &AppStoreConnectClient{
+		baseURI:  config.AppStoreConnectAPIURI,
+		keyId:    config.AppStoreConnectKeyId,
+		issuerId: config.AppStoreConnectIssuerId,
+		key:      key,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (c *AppStoreConnectClient) token() (string, error) {
+	now := time.Now()
+	return SignJWT(
+		map[string]string{"alg": "ES256", "kid": c.keyId, "typ": "JWT"},
+		map[string]interface{}{
+			"iss": c.issuerId,
+			"iat": now.Unix(),
+			"exp": now.Add(APP_STORE_CONNECT_TOKEN_TTL).Unix(),
+			"aud": APP_STORE_CONNECT_AUDIENCE,
+		},
+		ES256Signer(c.key),
+	)
+}
+
+// Get decodes the document at path, or at an absolute URI such as links.next.
+func (c *AppStoreConnectClient) Get(path string, v interface{}) error {
+	uri := path
+	if !strings.HasPrefix(path, "http") {
+		uri = c.baseURI + path
+	}
+	log.Println(uri)
+
+	token, err := c.token()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("%s responded %s: %s", uri, res.Status, body)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// AppStoreConnectSource fetches customer reviews of every territory through
+// the App Store Connect API, it replaces the RSS feed source when an API key
+// is configured.
+type AppStoreConnectSource struct {
+	config AppConfig
+	client *AppStoreConnectClient
+}
+
+func NewAppStoreConnectSource(config AppConfig) ReviewSource {
+	if config.AppStoreAppId == "" {
+		return nil
+	}
+
+	client, err := NewAppStoreConnectClient(config)
+	if err != nil {
+		log.Printf("App Store Connect key of %s: %v", config.Key(), err)
+		return nil
+	}
+	if client == nil {
+		return nil
+	}
+
+	return &AppStoreConnectSource{config, client}
+}
+
+func (s *AppStoreConnectSource) Name() string {
+	return APP_STORE_NAME
+}
+
+func (s *AppStoreConnectSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true, Countries: true, DeveloperResponses: true}
+}
+
+func (s *AppStoreConnectSource) RatingSummaries() ([]RatingSummary, error) {
+	return appStoreRatings(s.config)
+}
+
+type appStoreConnectReviews struct {
+	Data []struct {
+		Id         string `json:"id"`
+		Attributes struct {
+			Rating           int       `json:"rating"`
+			Title            string    `json:"title"`
+			Body             string    `json:"body"`
+			ReviewerNickname string    `json:"reviewerNickname"`
+			CreatedDate      time.Time `json:"createdDate"`
+			Territory        string    `json:"territory"`
+		} `json:"attributes"`
+		Relationships struct {
+			Response struct {
+				Data *struct {
+					Id string `json:"id"`
+				} `json:"data"`
+			} `json:"response"`
+		} `json:"relationships"`
+	} `json:"data"`
+	Included []struct {
+		Type       string `json:"type"`
+		Id         string `json:"id"`
+		Attributes struct {
+			ResponseBody     string    `json:"responseBody"`
+			LastModifiedDate time.Time `json:"lastModifiedDate"`
+		} `json:"attributes"`
+	} `json:"included"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+// Fetch follows links.next of the newest first reviews until a known review
+// or one older than since is reached.
+func (s *AppStoreConnectSource) Fetch(since time.Time) (Reviews, error) {
+	query := url.Values{}
+	query.Add("sort", "-createdDate")
+	query.Add("include", "response")
+	query.Add("limit", fmt.Sprint(APP_STORE_CONNECT_PAGE_SIZE))
+	next := fmt.Sprintf("/v1/apps/%s/customerReviews?%s", url.PathEscape(s.config.AppStoreAppId), query.Encode())
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for page := 1; page <= MaxPages(APP_STORE_CONNECT_MAX_PAGES) && next != ""; page++ {
+		var list appStoreConnectReviews
+		if err := s.client.Get(next, &list); err != nil {
+			return nil, err
+		}
+		next = list.Links.Next
+
+		responses := map[string]int{}
+		for i, included := range list.Included {
+			if included.Type == "customerReviewResponses" {
+				responses[included.Id] = i
+			}
+		}
+
+		pageReviews := Reviews{}
+		for i, entry := range list.Data {
+			attributes := entry.Attributes
+			if attributes.Rating < 1 || attributes.Rating > 5 {
+				parseErrors = append(parseErrors, &ParseError{
+					Source: APP_STORE_CONNECT_SOURCE_NAME,
+					Page:   page,
+					Entry:  i,
+					Err:    fmt.Errorf("invalid rating %d of review %s", attributes.Rating, entry.Id),
+				})
+				continue
+			}
+
+			country := TerritoryCountry(attributes.Territory)
+			review := Review{
+				ExternalID: entry.Id,
+				Author:     attributes.ReviewerNickname,
+				Store:      APP_STORE_NAME,
+				Country:    country,
+				Title:      attributes.Title,
+				Message:    attributes.Body,
+				Rating:     attributes.Rating,
+				Rate:       parseAppStoreRate(attributes.Rating),
+				UpdatedAt:  attributes.CreatedDate,
+				Permalink:  fmt.Sprintf("https://apps.apple.com/%s/app/id%s?see-all=reviews#%s", country, s.config.AppStoreAppId, entry.Id),
+			}
+
+			if response := entry.Relationships.Response.Data; response != nil {
+				if i, ok := responses[response.Id]; ok {
+					review.DeveloperResponse = list.Included[i].Attributes.ResponseBody
+					review.DeveloperResponseAt = list.Included[i].Attributes.LastModifiedDate
+				}
+			}
+
+			pageReviews = append(pageReviews, review)
+		}
+
+		unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, unseen...)
+		if done {
+			break
+		}
+	}
+
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Backfill stores the reviews of every app and source back to the date given
+// by the -since argument, without posting them. Backfilled reviews are marked
+// as notified so they stay quiet on later runs.
+func Backfill(config Config, args []string) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	sinceDate := flags.String("since", "", "oldest review date to store, as 2006-01-02")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *sinceDate == "" {
+		return fmt.Errorf("Backfill requires -since, such as -since 2016-01-01")
+	}
+	since, err := time.Parse("2006-01-02", *sinceDate)
+	if err != nil {
+		return fmt.Errorf("Invalid backfill date %s, expected 2006-01-02", *sinceDate)
+	}
+
+	backfilling = true
+	defer func() { backfilling = false }()
+
+	for _, app := range config.Apps {
+		for _, source := range NewSources(app) {
+			err := BackfillReviews(app, source, since)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Println("backfill done.")
+	return nil
+}
+
+// BackfillReviews walks every page of source back to since and stores the
+// reviews of app found on the way.
+func BackfillReviews(app AppConfig, source ReviewSource, since time.Time) error {
+	log.Printf("Backfilling %s reviews of %s since %s ...", source.Name(), app.Key(), since.Format("2006-01-02"))
+	if !source.Capabilities().Paginated {
+		log.Printf("%s only returns its latest reviews, older ones cannot be backfilled", source.Name())
+	}
+
+	reviews, err := source.Fetch(since)
+	if parseErrors, ok := err.(ParseErrors); ok {
+		for _, parseError := range parseErrors {
+			log.Println(parseError)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	for i := range reviews {
+		reviews[i].App = app.Key()
+	}
+
+	fetched := reviews
+	saved, err := SaveReviews(reviews)
+	if err != nil {
+		return err
+	}
+
+	err = saveWatermarks(app, source, fetched)
+	if err != nil {
+		return err
+	}
+
+	err = dbh.MarkNotified(saved.New)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%s backfill finished, %d reviews stored", source.Name(), len(saved.New))
+
+	return nil
+}
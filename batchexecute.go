@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// BATCH_EXECUTE_PREFIX guards batchexecute responses against JSON hijacking.
+const BATCH_EXECUTE_PREFIX = ")]}'"
+
+// BatchExecute calls a single rpc of a Google batchexecute endpoint, as used
+// by the Play Store web front end, and returns its decoded payload.
+func BatchExecute(client *http.Client, uri string, rpcId string, args interface{}) (json.RawMessage, error) {
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := json.Marshal([][][]interface{}{{{rpcId, string(encodedArgs), nil, "generic"}}})
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Add("f.req", string(envelope))
+
+	req, err := http.NewRequest("POST", uri, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded %s", uri, res.Status)
+	}
+
+	return DecodeBatchExecute(res.Body, rpcId)
+}
+
+// DecodeBatchExecute finds the payload of rpcId in a batchexecute response.
+// The response is the guard prefix followed by chunks, each one a length
+// line and a JSON array of envelopes such as ["wrb.fr", rpcId, payload, ...].
+// The payload itself is JSON encoded in a string.
+func DecodeBatchExecute(r io.Reader, rpcId string) (json.RawMessage, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	body = bytes.TrimPrefix(bytes.TrimSpace(body), []byte(BATCH_EXECUTE_PREFIX))
+
+	// length lines are JSON numbers, so chunks decode as a stream of values
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var chunk interface{}
+		err := decoder.Decode(&chunk)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding batchexecute response failed: %v", err)
+		}
+
+		envelopes, ok := chunk.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, envelope := range envelopes {
+			if JSONString(envelope, 0) != "wrb.fr" || JSONString(envelope, 1) != rpcId {
+				continue
+			}
+
+			payload := JSONString(envelope, 2)
+			if payload == "" {
+				return nil, fmt.Errorf("batchexecute rpc %s failed: %v", rpcId, JSONValue(envelope, 5))
+			}
+			return json.RawMessage(payload), nil
+		}
+	}
+
+	return nil, fmt.Errorf("batchexecute response has no %s payload", rpcId)
+}
+
+// JSONValue walks nested arrays of a decoded JSON value by index,
+// it returns nil when any step is missing.
+func JSONValue(v interface{}, path ...int) interface{} {
+	for _, i := range path {
+		array, ok := v.([]interface{})
+		if !ok || i < 0 || i >= len(array) {
+			return nil
+		}
+		v = array[i]
+	}
+	return v
+}
+
+// JSONString returns the string at path, or "" when it is missing.
+func JSONString(v interface{}, path ...int) string {
+	s, _ := JSONValue(v, path...).(string)
+	return s
+}
+
+// JSONInt returns the number at path, or 0 when it is missing.
+func JSONInt(v interface{}, path ...int) int64 {
+	n, _ := JSONValue(v, path...).(float64)
+	return int64(n)
+}
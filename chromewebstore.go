@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	CHROME_WEB_STORE_NAME              = "Chrome Web Store"
+	CHROME_WEB_STORE_BASE_URI          = "https://chromewebstore.google.com"
+	CHROME_WEB_STORE_BATCH_EXECUTE_URI = CHROME_WEB_STORE_BASE_URI + "/_/ChromeWebStoreConsumerFeUi/data/batchexecute"
+	CHROME_WEB_STORE_REVIEWS_RPC       = "x1DgCd"
+	CHROME_WEB_STORE_DEFAULT_LOCALE    = "en"
+	CHROME_WEB_STORE_PAGE_SIZE         = 25
+	CHROME_WEB_STORE_MAX_PAGES         = 10
+	// CHROME_WEB_STORE_SORT_NEWEST is the sort id of the reviews rpc listing
+	// the most recent reviews first.
+	CHROME_WEB_STORE_SORT_NEWEST = 2
+)
+
+var chromeWebStoreClient = &http.Client{Timeout: 30 * time.Second}
+
+func init() {
+	RegisterSource(CHROME_WEB_STORE_NAME, NewChromeWebStoreSource)
+}
+
+type ChromeWebStoreSource struct {
+	config AppConfig
+}
+
+func NewChromeWebStoreSource(config AppConfig) ReviewSource {
+	if config.ChromeWebStoreItemId == "" {
+		return nil
+	}
+	return &ChromeWebStoreSource{config}
+}
+
+func (s *ChromeWebStoreSource) Name() string {
+	return CHROME_WEB_STORE_NAME
+}
+
+func (s *ChromeWebStoreSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true, DeveloperResponses: true}
+}
+
+// Fetch walks the newest first review pages until a known review or one
+// older than since is reached.
+func (s *ChromeWebStoreSource) Fetch(since time.Time) (Reviews, error) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	token := ""
+	for page := 1; page <= MaxPages(CHROME_WEB_STORE_MAX_PAGES); page++ {
+		pageReviews, pageErrors, next, err := GetChromeWebStoreReviews(s.config, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, pageError := range pageErrors {
+			pageError.Page = page
+		}
+		parseErrors = append(parseErrors, pageErrors...)
+
+		unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, unseen...)
+		if done || next == "" {
+			break
+		}
+		token = next
+	}
+
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
+
+// GetChromeWebStoreReviews returns a page of reviews through the reviews rpc
+// of the Chrome Web Store front end, and the token of the next page if any.
+func GetChromeWebStoreReviews(config AppConfig, token string) (Reviews, ParseErrors, string, error) {
+	log.Println(fmt.Sprintf("id: %s, hl: %s", config.ChromeWebStoreItemId, config.ChromeWebStoreLocale))
+
+	query := url.Values{}
+	query.Add("hl", config.ChromeWebStoreLocale)
+	uri := CHROME_WEB_STORE_BATCH_EXECUTE_URI + "?" + query.Encode()
+
+	payload, err := BatchExecute(chromeWebStoreClient, uri, CHROME_WEB_STORE_REVIEWS_RPC, chromeWebStoreReviewsArgs(config, token))
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return ParseChromeWebStoreReviews(config, payload)
+}
+
+// ParseChromeWebStoreReviews maps the payload of the reviews rpc, laid out
+// as [[review, ...], token].
+func ParseChromeWebStoreReviews(config AppConfig, payload json.RawMessage) (Reviews, ParseErrors, string, error) {
+	var data interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, nil, "", fmt.Errorf("decoding Chrome Web Store reviews failed: %v", err)
+	}
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	entries, _ := JSONValue(data, 0).([]interface{})
+	for i, entry := range entries {
+		review, err := parseChromeWebStoreEntry(config, entry)
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{
+				Source: CHROME_WEB_STORE_NAME,
+				Entry:  i,
+				Err:    err,
+			})
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	sort.Sort(reviews)
+	return reviews, parseErrors, JSONString(data, 1), nil
+}
+
+// chromeWebStoreReviewsArgs builds the reviews rpc arguments:
+// [item id, null, [count, token], sort]
+func chromeWebStoreReviewsArgs(config AppConfig, token string) []interface{} {
+	var pageToken interface{}
+	if token != "" {
+		pageToken = token
+	}
+
+	return []interface{}{
+		config.ChromeWebStoreItemId,
+		nil,
+		[]interface{}{CHROME_WEB_STORE_PAGE_SIZE, pageToken},
+		CHROME_WEB_STORE_SORT_NEWEST,
+	}
+}
+
+// parseChromeWebStoreEntry maps a review of the reviews rpc, laid out as
+// [id, [author, avatar], rating, text, [seconds, nanos], version, language,
+// [reply, [seconds, nanos]]].
+func parseChromeWebStoreEntry(config AppConfig, entry interface{}) (Review, error) {
+	id := JSONString(entry, 0)
+	if id == "" {
+		return Review{}, fmt.Errorf("missing review id")
+	}
+
+	rate := int(JSONInt(entry, 2))
+	if rate < 1 || rate > 5 {
+		return Review{}, fmt.Errorf("invalid rating %v of review %s", JSONValue(entry, 2), id)
+	}
+
+	seconds := JSONInt(entry, 4, 0)
+	if seconds == 0 {
+		return Review{}, fmt.Errorf("missing date of review %s", id)
+	}
+
+	return Review{
+		ExternalID: id,
+		Author:     JSONString(entry, 1, 0),
+		Store:      CHROME_WEB_STORE_NAME,
+		Title:      "No title provided",
+		Message:    strings.TrimSpace(JSONString(entry, 3)),
+		Rating:     rate,
+		Rate:       parseAppStoreRate(rate),
+		UpdatedAt:  googlePlayTime(seconds),
+		Permalink:  CHROME_WEB_STORE_BASE_URI + "/detail/" + url.PathEscape(config.ChromeWebStoreItemId) + "/reviews",
+		AppVersion: JSONString(entry, 5),
+		Language:   JSONString(entry, 6),
+
+		DeveloperResponse:   JSONString(entry, 7, 0),
+		DeveloperResponseAt: googlePlayTime(JSONInt(entry, 7, 1, 0)),
+	}, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChromeWebStoreReviews(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/chromewebstore_reviews.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var request []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.Unmarshal([]byte(r.FormValue("f.req")), &request); err != nil {
+			t.Errorf("decoding f.req failed: %v", err)
+		}
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	config := AppConfig{ChromeWebStoreItemId: "abcdefghijklmnopabcdefghijklmnop"}
+	payload, err := BatchExecute(server.Client(), server.URL, CHROME_WEB_STORE_REVIEWS_RPC, chromeWebStoreReviewsArgs(config, "CAoSAggB"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rpcId := JSONString(request, 0, 0, 0); rpcId != CHROME_WEB_STORE_REVIEWS_RPC {
+		t.Errorf("rpc id is %q", rpcId)
+	}
+	want := `["abcdefghijklmnopabcdefghijklmnop",null,[25,"CAoSAggB"],2]`
+	if args := JSONString(request, 0, 0, 1); args != want {
+		t.Errorf("rpc args are %s, want %s", args, want)
+	}
+
+	reviews, parseErrors, next, err := ParseChromeWebStoreReviews(config, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != "CAoSBggBEgIIAQ" {
+		t.Errorf("next page token is %q", next)
+	}
+	if len(parseErrors) != 1 || parseErrors[0].Entry != 2 {
+		t.Errorf("skipped entries are %v, want entry 2", parseErrors)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("%d reviews, want 2", len(reviews))
+	}
+
+	review := reviews[0]
+	if review.ExternalID != "b9c1f2a4-7d3e-4c55-9a61-1f0e2d3c4b5a" || review.Author != "Jane Doe" || review.Rating != 5 ||
+		review.Message != "Saves me so much time every morning." || review.AppVersion != "2.4.1" || review.Language != "en" {
+		t.Errorf("first review is %+v", review)
+	}
+	if !review.UpdatedAt.Equal(time.Unix(1672653600, 0)) {
+		t.Errorf("first review is dated %v", review.UpdatedAt)
+	}
+	if review.DeveloperResponse != "Thanks Jane, glad it helps!" || !review.DeveloperResponseAt.Equal(time.Unix(1672740000, 0)) {
+		t.Errorf("first review response is %q at %v", review.DeveloperResponse, review.DeveloperResponseAt)
+	}
+	if review.Permalink != CHROME_WEB_STORE_BASE_URI+"/detail/abcdefghijklmnopabcdefghijklmnop/reviews" {
+		t.Errorf("first review permalink is %s", review.Permalink)
+	}
+
+	review = reviews[1]
+	if review.ExternalID != "0d7e6f5a-4b3c-4a2b-8c1d-9e8f7a6b5c4d" || review.Rating != 2 ||
+		review.Message != "Stopped syncing after the last update." || review.Language != "de" || review.DeveloperResponse != "" {
+		t.Errorf("second review is %+v", review)
+	}
+}
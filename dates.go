@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// LOCALE_MONTHS lists month names by language as they appear in dates, which
+// is the genitive form in many languages. Alternative spellings of a month
+// are separated by "|". Abbreviations are matched as unique prefixes.
+var LOCALE_MONTHS = map[string][]string{
+	"en":  {"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"},
+	"de":  {"januar|jänner", "februar", "märz", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "dezember"},
+	"fr":  {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	"it":  {"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	"es":  {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre|setiembre", "octubre", "noviembre", "diciembre"},
+	"pt":  {"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	"ca":  {"gener", "febrer", "març", "abril", "maig", "juny", "juliol", "agost", "setembre", "octubre", "novembre", "desembre"},
+	"nl":  {"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+	"sv":  {"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september", "oktober", "november", "december"},
+	"da":  {"januar", "februar", "marts", "april", "maj", "juni", "juli", "august", "september", "oktober", "november", "december"},
+	"nb":  {"januar", "februar", "mars", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "desember"},
+	"fi":  {"tammikuuta", "helmikuuta", "maaliskuuta", "huhtikuuta", "toukokuuta", "kesäkuuta", "heinäkuuta", "elokuuta", "syyskuuta", "lokakuuta", "marraskuuta", "joulukuuta"},
+	"et":  {"jaanuar", "veebruar", "märts", "aprill", "mai", "juuni", "juuli", "august", "september", "oktoober", "november", "detsember"},
+	"lt":  {"sausio", "vasario", "kovo", "balandžio", "gegužės", "birželio", "liepos", "rugpjūčio", "rugsėjo", "spalio", "lapkričio", "gruodžio"},
+	"pl":  {"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+	"cs":  {"ledna", "února", "března", "dubna", "května", "června", "července", "srpna", "září", "října", "listopadu", "prosince"},
+	"sk":  {"januára", "februára", "marca", "apríla", "mája", "júna", "júla", "augusta", "septembra", "októbra", "novembra", "decembra"},
+	"sl":  {"januar", "februar", "marec", "april", "maj", "junij", "julij", "avgust", "september", "oktober", "november", "december"},
+	"hr":  {"siječnja", "veljače", "ožujka", "travnja", "svibnja", "lipnja", "srpnja", "kolovoza", "rujna", "listopada", "studenoga|studenog", "prosinca"},
+	"sr":  {"јануар", "фебруар", "март", "април", "мај", "јун", "јул", "август", "септембар", "октобар", "новембар", "децембар"},
+	"hu":  {"január", "február", "március", "április", "május", "június", "július", "augusztus", "szeptember", "október", "november", "december"},
+	"ro":  {"ianuarie", "februarie", "martie", "aprilie", "mai", "iunie", "iulie", "august", "septembrie", "octombrie", "noiembrie", "decembrie"},
+	"bg":  {"януари", "февруари", "март", "април", "май", "юни", "юли", "август", "септември", "октомври", "ноември", "декември"},
+	"ru":  {"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+	"uk":  {"січня", "лютого", "березня", "квітня", "травня", "червня", "липня", "серпня", "вересня", "жовтня", "листопада", "грудня"},
+	"el":  {"ιανουαρίου", "φεβρουαρίου", "μαρτίου", "απριλίου", "μαΐου", "ιουνίου", "ιουλίου", "αυγούστου", "σεπτεμβρίου", "οκτωβρίου", "νοεμβρίου", "δεκεμβρίου"},
+	"tr":  {"ocak", "şubat", "mart", "nisan", "mayıs", "haziran", "temmuz", "ağustos", "eylül", "ekim", "kasım", "aralık"},
+	"id":  {"januari", "februari", "maret", "april", "mei", "juni", "juli", "agustus", "september", "oktober", "november", "desember"},
+	"ms":  {"januari", "februari", "mac", "april", "mei", "jun", "julai", "ogos", "september", "oktober", "november", "disember"},
+	"fil": {"enero", "pebrero", "marso", "abril", "mayo", "hunyo", "hulyo", "agosto", "setyembre", "oktubre", "nobyembre", "disyembre"},
+	"hi":  {"जनवरी", "फ़रवरी|फरवरी", "मार्च", "अप्रैल", "मई", "जून", "जुलाई", "अगस्त", "सितंबर|सितम्बर", "अक्तूबर|अक्टूबर", "नवंबर|नवम्बर", "दिसंबर|दिसम्बर"},
+	"th":  {"มกราคม|ม.ค.", "กุมภาพันธ์|ก.พ.", "มีนาคม|มี.ค.", "เมษายน|เม.ย.", "พฤษภาคม|พ.ค.", "มิถุนายน|มิ.ย.", "กรกฎาคม|ก.ค.", "สิงหาคม|ส.ค.", "กันยายน|ก.ย.", "ตุลาคม|ต.ค.", "พฤศจิกายน|พ.ย.", "ธันวาคม|ธ.ค."},
+	"ar":  {"يناير", "فبراير", "مارس", "أبريل|ابريل", "مايو", "يونيو", "يوليو", "أغسطس|اغسطس", "سبتمبر", "أكتوبر|اكتوبر", "نوفمبر", "ديسمبر"},
+	"fa":  {"ژانویه", "فوریه", "مارس", "آوریل", "مه|مى", "ژوئن", "ژوئیه|جولای", "اوت|اگوست", "سپتامبر", "اکتبر", "نوامبر", "دسامبر"},
+	"he":  {"בינואר|ינואר", "בפברואר|פברואר", "במרץ|מרץ", "באפריל|אפריל", "במאי|מאי", "ביוני|יוני", "ביולי|יולי", "באוגוסט|אוגוסט", "בספטמבר|ספטמבר", "באוקטובר|אוקטובר", "בנובמבר|נובמבר", "בדצמבר|דצמבר"},
+}
+
+// LOCALE_LANGUAGE_ALIASES maps language codes sharing the month names of another.
+var LOCALE_LANGUAGE_ALIASES = map[string]string{
+	"no": "nb",
+	"nn": "nb",
+	"tl": "fil",
+	"iw": "he",
+	"in": "id",
+	"gl": "es",
+	"bs": "hr",
+}
+
+// MONTH_FIRST_LOCALES write numeric dates as month/day/year. English
+// without a country is read as American English.
+var MONTH_FIRST_LOCALES = map[string]bool{
+	"en":     true,
+	"en_US":  true,
+	"en_PH":  true,
+	"fil":    true,
+	"fil_PH": true,
+}
+
+// BUDDHIST_ERA_OFFSET is the difference between Thai and Gregorian years.
+const BUDDHIST_ERA_OFFSET = 543
+
+var (
+	cjkDate     = regexp.MustCompile(`(\d{4})\s*[年년]\s*(\d{1,2})\s*[月월]\s*(\d{1,2})\s*[日일]?`)
+	clockTime   = regexp.MustCompile(`(\d{1,2}):(\d{2})(?::(\d{2}))?`)
+	vietMonth   = regexp.MustCompile(`(?i)tháng\s*(\d{1,2})`)
+	dateNumbers = regexp.MustCompile(`\d+`)
+	// bidiMarks are directional formatting characters found in right to left dates.
+	bidiMarks = strings.NewReplacer("\u200e", "", "\u200f", "", "\u061c", "", "\u202a", "", "\u202b", "", "\u202c", "")
+)
+
+// NormalizeLocale turns locale tags such as "zh-tw", "ZH_tw" or "pt-br" into
+// the form zh_TW, scripts are title cased as in zh_Hant.
+func NormalizeLocale(tag string) string {
+	parts := strings.FieldsFunc(strings.TrimSpace(tag), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(parts) == 0 {
+		return ""
+	}
+
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		default:
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// LocaleLanguage returns the language of a locale tag, "zh_TW" is "zh".
+func LocaleLanguage(locale string) string {
+	language := NormalizeLocale(locale)
+	if i := strings.Index(language, "_"); i >= 0 {
+		language = language[:i]
+	}
+	if alias, ok := LOCALE_LANGUAGE_ALIASES[language]; ok {
+		return alias
+	}
+	return language
+}
+
+// ParseLocalDate parses a date written for locale, such as "2 января 2023 г.",
+// "2023年1月2日", "٢ يناير ٢٠٢٣" or "01/02/2023", along with a time of day if
+// any, in loc. English month names are understood in every locale.
+func ParseLocalDate(locale string, text string, loc *time.Location) (time.Time, error) {
+	locale = NormalizeLocale(locale)
+	language := LocaleLanguage(locale)
+	normalized := normalizeDigits(bidiMarks.Replace(text))
+
+	hour, minute, second := 0, 0, 0
+	if match := clockTime.FindStringSubmatch(normalized); match != nil {
+		hour, _ = strconv.Atoi(match[1])
+		minute, _ = strconv.Atoi(match[2])
+		second, _ = strconv.Atoi(match[3])
+		normalized = strings.Replace(normalized, match[0], " ", 1)
+	}
+
+	year, month, day, err := parseLocalDay(locale, language, normalized)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%v in %q", err, text)
+	}
+
+	if language == "th" && year > 1900+BUDDHIST_ERA_OFFSET {
+		year -= BUDDHIST_ERA_OFFSET
+	}
+
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, fmt.Errorf("invalid date %q", text)
+	}
+	date := time.Date(year, time.Month(month), day, hour, minute, second, 0, loc)
+	if date.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date %q", text)
+	}
+	return date, nil
+}
+
+func parseLocalDay(locale string, language string, text string) (year int, month int, day int, err error) {
+	if match := cjkDate.FindStringSubmatch(text); match != nil {
+		year, _ = strconv.Atoi(match[1])
+		month, _ = strconv.Atoi(match[2])
+		day, _ = strconv.Atoi(match[3])
+		return year, month, day, nil
+	}
+
+	if match := vietMonth.FindStringSubmatch(text); match != nil {
+		month, _ = strconv.Atoi(match[1])
+		text = strings.Replace(text, match[0], " ", 1)
+	} else {
+		month = findMonthName(language, text)
+	}
+
+	numbers := dateNumbers.FindAllString(text, -1)
+	if month > 0 {
+		// the year has four digits, the day is the remaining number
+		for _, number := range numbers {
+			n, _ := strconv.Atoi(number)
+			switch {
+			case len(number) == 4 && year == 0:
+				year = n
+			case len(number) <= 2 && day == 0:
+				day = n
+			}
+		}
+		if year == 0 || day == 0 {
+			return 0, 0, 0, fmt.Errorf("incomplete date")
+		}
+		return year, month, day, nil
+	}
+
+	if len(numbers) < 3 {
+		return 0, 0, 0, fmt.Errorf("no date found")
+	}
+	a, _ := strconv.Atoi(numbers[0])
+	b, _ := strconv.Atoi(numbers[1])
+	c, _ := strconv.Atoi(numbers[2])
+	switch {
+	case len(numbers[0]) == 4:
+		return a, b, c, nil
+	case len(numbers[2]) == 4 && MONTH_FIRST_LOCALES[locale]:
+		return c, a, b, nil
+	case len(numbers[2]) == 4:
+		return c, b, a, nil
+	}
+	return 0, 0, 0, fmt.Errorf("no year found")
+}
+
+// findMonthName returns the month of the first month name of text in the
+// language, or in English, 0 when none is found.
+func findMonthName(language string, text string) int {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		// thai abbreviations carry dots, vowel signs of many scripts are marks
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !(language == "th" && r == '.')
+	})
+
+	for _, months := range [][]string{LOCALE_MONTHS[language], LOCALE_MONTHS["en"]} {
+		for _, word := range words {
+			if month := matchMonth(months, word); month > 0 {
+				return month
+			}
+		}
+	}
+	return 0
+}
+
+// matchMonth matches a word to a month name, or to the unique month name it
+// abbreviates.
+func matchMonth(months []string, word string) int {
+	prefixed, ambiguous := 0, false
+	for i, names := range months {
+		for _, name := range strings.Split(names, "|") {
+			if word == name {
+				return i + 1
+			}
+			if len([]rune(word)) >= 3 && strings.HasPrefix(name, word) {
+				ambiguous = ambiguous || (prefixed > 0 && prefixed != i+1)
+				prefixed = i + 1
+			}
+		}
+	}
+	if ambiguous {
+		return 0
+	}
+	return prefixed
+}
+
+// normalizeDigits turns Arabic-Indic, Persian, Devanagari and Thai digits
+// into ASCII digits.
+func normalizeDigits(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '٠' && r <= '٩':
+			return '0' + r - '٠'
+		case r >= '۰' && r <= '۹':
+			return '0' + r - '۰'
+		case r >= '०' && r <= '९':
+			return '0' + r - '०'
+		case r >= '๐' && r <= '๙':
+			return '0' + r - '๐'
+		}
+		return r
+	}, text)
+}
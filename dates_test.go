@@ -0,0 +1,163 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+var localeDateTests = []struct {
+	locale string
+	text   string
+	want   string
+}{
+	// month names, one case per LOCALE_MONTHS language
+	{"en_US", "Reviewed in the United States on January 2, 2023", "2023-01-02 00:00"},
+	{"de_DE", "2. März 2023", "2023-03-02 00:00"},
+	{"fr_FR", "14 février 2023", "2023-02-14 00:00"},
+	{"it_IT", "3 aprile 2023", "2023-04-03 00:00"},
+	{"es_ES", "5 de septiembre de 2023", "2023-09-05 00:00"},
+	{"pt_BR", "12 de junho de 2023", "2023-06-12 00:00"},
+	{"ca", "7 de juliol de 2023", "2023-07-07 00:00"},
+	{"nl", "21 augustus 2023", "2023-08-21 00:00"},
+	{"sv", "4 maj 2023", "2023-05-04 00:00"},
+	{"da", "9. december 2023", "2023-12-09 00:00"},
+	{"no", "1. oktober 2023", "2023-10-01 00:00"},
+	{"fi", "15. marraskuuta 2023", "2023-11-15 00:00"},
+	{"et", "6. veebruar 2023", "2023-02-06 00:00"},
+	{"lt", "2023 m. sausio 10 d.", "2023-01-10 00:00"},
+	{"pl", "8 lutego 2023", "2023-02-08 00:00"},
+	{"cs", "11. května 2023", "2023-05-11 00:00"},
+	{"sk", "13. júla 2023", "2023-07-13 00:00"},
+	{"sl", "17. avgust 2023", "2023-08-17 00:00"},
+	{"hr", "20. studenoga 2023.", "2023-11-20 00:00"},
+	{"sr", "25. децембар 2023.", "2023-12-25 00:00"},
+	{"hu", "2023. március 3.", "2023-03-03 00:00"},
+	{"ro", "19 ianuarie 2023", "2023-01-19 00:00"},
+	{"bg", "22 април 2023 г.", "2023-04-22 00:00"},
+	{"ru", "2 января 2023 г.", "2023-01-02 00:00"},
+	{"uk", "30 вересня 2023 р.", "2023-09-30 00:00"},
+	{"el", "18 Μαΐου 2023", "2023-05-18 00:00"},
+	{"tr", "28 Ekim 2023", "2023-10-28 00:00"},
+	{"id", "16 Agustus 2023", "2023-08-16 00:00"},
+	{"ms", "5 Ogos 2023", "2023-08-05 00:00"},
+	{"fil", "Hunyo 3, 2023", "2023-06-03 00:00"},
+	{"hi", "7 सितंबर 2023", "2023-09-07 00:00"},
+	{"th", "12 ม.ค. 2566", "2023-01-12 00:00"},
+	{"ar", "\u200f٢ يناير ٢٠٢٣", "2023-01-02 00:00"},
+	{"fa", "۱۵ مه ۲۰۲۳", "2023-05-15 00:00"},
+	{"he", "3 במרץ 2023", "2023-03-03 00:00"},
+
+	// abbreviations and English names in other locales
+	{"en_GB", "2 Sept 2023", "2023-09-02 00:00"},
+	{"de", "Jan 2, 2023", "2023-01-02 00:00"},
+
+	// CJK and Vietnamese
+	{"zh_TW", "2023年1月2日", "2023-01-02 00:00"},
+	{"zh_CN", "2023年12月31日 08:30", "2023-12-31 08:30"},
+	{"ja_JP", "2023年1月2日に日本でレビュー済み", "2023-01-02 00:00"},
+	{"ko", "2023년 1월 2일", "2023-01-02 00:00"},
+	{"vi", "2 tháng 1, 2023", "2023-01-02 00:00"},
+
+	// numeric dates, one case per MONTH_FIRST_LOCALES entry
+	{"en", "01/02/2023", "2023-01-02 00:00"},
+	{"en_US", "01/02/2023", "2023-01-02 00:00"},
+	{"en_PH", "01/02/2023", "2023-01-02 00:00"},
+	{"fil", "01/02/2023", "2023-01-02 00:00"},
+	{"fil_PH", "01/02/2023", "2023-01-02 00:00"},
+	{"en_GB", "01/02/2023", "2023-02-01 00:00"},
+	{"de_DE", "02.01.2023 15:04", "2023-01-02 15:04"},
+	{"th", "02/01/2566", "2023-01-02 00:00"},
+	{"ar", "٠٢/٠١/٢٠٢٣", "2023-01-02 00:00"},
+	{"zh_CN", "2023-01-02 15:04:05", "2023-01-02 15:04"},
+}
+
+func TestParseLocalDate(t *testing.T) {
+	for _, test := range localeDateTests {
+		date, err := ParseLocalDate(test.locale, test.text, time.UTC)
+		if err != nil {
+			t.Errorf("ParseLocalDate(%q, %q) failed: %v", test.locale, test.text, err)
+			continue
+		}
+		if got := date.Format("2006-01-02 15:04"); got != test.want {
+			t.Errorf("ParseLocalDate(%q, %q) = %s, want %s", test.locale, test.text, got, test.want)
+		}
+	}
+}
+
+func TestParseLocalDateCoversLocales(t *testing.T) {
+	languages := map[string]bool{}
+	monthFirst := map[string]bool{}
+	for _, test := range localeDateTests {
+		languages[LocaleLanguage(test.locale)] = true
+		if test.text == "01/02/2023" {
+			monthFirst[NormalizeLocale(test.locale)] = true
+		}
+	}
+
+	for language := range LOCALE_MONTHS {
+		if !languages[language] {
+			t.Errorf("no date test for language %s", language)
+		}
+	}
+	for locale := range MONTH_FIRST_LOCALES {
+		if !monthFirst[locale] {
+			t.Errorf("no month first date test for locale %s", locale)
+		}
+	}
+}
+
+func TestParseLocalDateLocation(t *testing.T) {
+	date, err := ParseLocalDate("zh_CN", "2023-01-02 08:00", chinaStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC); !date.Equal(want) {
+		t.Errorf("got %v, want %v", date.UTC(), want)
+	}
+}
+
+func TestParseLocalDateErrors(t *testing.T) {
+	for _, test := range []struct {
+		locale string
+		text   string
+	}{
+		{"en_US", "yesterday"},
+		{"en_US", "January 2023"},
+		{"en_GB", "31/02/2023"},
+		{"en_US", "13/01/2023"},
+		{"de", "2. März 2023 25:00"},
+	} {
+		if date, err := ParseLocalDate(test.locale, test.text, time.UTC); err == nil {
+			t.Errorf("ParseLocalDate(%q, %q) = %v, want an error", test.locale, test.text, date)
+		}
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	for tag, want := range map[string]string{
+		"zh-tw":      "zh_TW",
+		"ZH_tw":      "zh_TW",
+		"pt-br":      "pt_BR",
+		"zh-hant-tw": "zh_Hant_TW",
+		" en ":       "en",
+		"":           "",
+	} {
+		if got := NormalizeLocale(tag); got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestLocaleLanguage(t *testing.T) {
+	for locale, want := range map[string]string{
+		"zh_TW": "zh",
+		"no-NO": "nb",
+		"iw":    "he",
+		"tl_PH": "fil",
+		"en":    "en",
+	} {
+		if got := LocaleLanguage(locale); got != want {
+			t.Errorf("LocaleLanguage(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DIGEST_DEFAULT_INTERVAL = 24 * time.Hour
+	// DIGEST_TOP_COMPLAINTS is the number of low rated reviews quoted by digests.
+	DIGEST_TOP_COMPLAINTS = 5
+	// DIGEST_COMPLAINT_RATING is the highest rating counted as a complaint.
+	DIGEST_COMPLAINT_RATING = 2
+)
+
+// Digest summarizes the reviews of an app stored since the previous digest.
+type Digest struct {
+	Since time.Time
+	// Counts are the new reviews per store.
+	Counts map[string]int
+	// Ratings counts new reviews per star rating, unrated reviews are left out.
+	Ratings map[int]int
+	// Recommended and NotRecommended count new Steam reviews, which are
+	// stored with star ratings but are not ones.
+	Recommended    int
+	NotRecommended int
+	Complaints     Reviews
+	// LastId is the newest review covered by the digest.
+	LastId int
+}
+
+// DigestInterval returns how often digests of app are posted.
+func (app AppConfig) DigestInterval() time.Duration {
+	interval, err := time.ParseDuration(app.DigestEvery)
+	if err != nil || interval <= 0 {
+		return DIGEST_DEFAULT_INTERVAL
+	}
+	return interval
+}
+
+// lastDigest returns the newest review and the time of the previous digest
+// of app, found is false before the first one.
+func (dbh *DBH) lastDigest(app string) (reviewId int, sentAt time.Time, found bool, err error) {
+	row := dbh.QueryRow(`SELECT review_id, sent_at FROM `+DIGEST_TABLE_NAME+` WHERE app = $1`, app)
+	err = row.Scan(&reviewId, &sentAt)
+	if err == sql.ErrNoRows {
+		return 0, time.Time{}, false, nil
+	}
+	return reviewId, sentAt, err == nil, err
+}
+
+func (dbh *DBH) setDigest(app string, reviewId int, sentAt time.Time) error {
+	_, err := dbh.Exec(`INSERT INTO `+DIGEST_TABLE_NAME+` (app, review_id, sent_at) VALUES ($1, $2, $3)
+		ON CONFLICT (app) DO UPDATE SET review_id = EXCLUDED.review_id, sent_at = EXCLUDED.sent_at`,
+		app, reviewId, sentAt)
+	return err
+}
+
+// NewDigest summarizes the reviews of app stored after review afterId.
+func (dbh *DBH) NewDigest(app string, afterId int) (Digest, error) {
+	digest := Digest{Counts: map[string]int{}, Ratings: map[int]int{}, Complaints: Reviews{}, LastId: afterId}
+
+	rows, err := dbh.Query(`SELECT store, rating, COUNT(*), MAX(id) FROM `+TABLE_NAME+` WHERE app = $1 AND id > $2 GROUP BY store, rating`, app, afterId)
+	if err != nil {
+		return digest, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var store string
+		var rating, count, lastId int
+		if err := rows.Scan(&store, &rating, &count, &lastId); err != nil {
+			return digest, err
+		}
+		digest.Counts[store] += count
+		if store == STEAM_NAME {
+			if rating == STEAM_RECOMMENDED_RATING {
+				digest.Recommended += count
+			} else {
+				digest.NotRecommended += count
+			}
+		} else if rating > 0 {
+			digest.Ratings[rating] += count
+		}
+		if lastId > digest.LastId {
+			digest.LastId = lastId
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return digest, err
+	}
+
+	// the lowest rated and most detailed reviews come first
+	rows, err = dbh.Query(`SELECT store, author, rating, title, message, comment_uri FROM `+TABLE_NAME+`
+		WHERE app = $1 AND id > $2 AND id <= $3 AND rating BETWEEN 1 AND $4
+		ORDER BY rating, LENGTH(message) DESC LIMIT $5`,
+		app, afterId, digest.LastId, DIGEST_COMPLAINT_RATING, DIGEST_TOP_COMPLAINTS)
+	if err != nil {
+		return digest, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		review := Review{}
+		var author, permalink sql.NullString
+		if err := rows.Scan(&review.Store, &author, &review.Rating, &review.Title, &review.Message, &permalink); err != nil {
+			return digest, err
+		}
+		review.Author = author.String
+		review.Permalink = permalink.String
+		review.Rate = StoredRate(review.Store, review.Rating)
+		digest.Complaints = append(digest.Complaints, review)
+	}
+	return digest, rows.Err()
+}
+
+// PostDigestIfDue posts the digest of a competitor app once its interval has
+// passed since the previous one. The first run only records where digests
+// start, so reviews stored by it are not reported.
+func PostDigestIfDue(app AppConfig) error {
+	lastId, sentAt, found, err := dbh.lastDigest(app.Key())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if found && now.Sub(sentAt) < app.DigestInterval() {
+		return nil
+	}
+
+	digest, err := dbh.NewDigest(app.Key(), lastId)
+	if err != nil {
+		return err
+	}
+	digest.Since = sentAt
+
+	if found {
+		if err := PostDigest(app, digest); err != nil {
+			return err
+		}
+	}
+
+	return dbh.setDigest(app.Key(), digest.LastId, now)
+}
+
+// PostDigest posts new review counts, the rating distribution and the top
+// complaints of a digest to the digest webhook.
+func PostDigest(config AppConfig, digest Digest) error {
+	total := 0
+	stores := []string{}
+	for store, count := range digest.Counts {
+		total += count
+		stores = append(stores, fmt.Sprintf("%s: %d", store, count))
+	}
+	sort.Strings(stores)
+
+	fields := []SlackAttachmentField{
+		{
+			Title: "New Reviews",
+			Value: fmt.Sprintf("%d", total),
+			Short: true,
+		},
+		{
+			Title: "Since",
+			Value: digest.Since.Format("2006-01-02"),
+			Short: true,
+		},
+	}
+	if len(stores) > 0 {
+		fields = append(fields, SlackAttachmentField{
+			Title: "Stores",
+			Value: strings.Join(stores, "\n"),
+			Short: true,
+		})
+	}
+	if distribution := ratingDistribution(digest.Ratings); distribution != "" {
+		fields = append(fields, SlackAttachmentField{
+			Title: "Ratings",
+			Value: distribution,
+			Short: true,
+		})
+	}
+	if split := recommendationSplit(digest.Recommended, digest.NotRecommended); split != "" {
+		fields = append(fields, SlackAttachmentField{
+			Title: "Recommendations",
+			Value: split,
+			Short: true,
+		})
+	}
+
+	attachments := []SlackAttachment{
+		{
+			Title:    "Summary",
+			Fallback: fmt.Sprintf("%d new reviews", total),
+			Fields:   fields,
+		},
+	}
+
+	for _, review := range digest.Complaints {
+		quote := []rune(review.Message)
+		if len(quote) > MAX_QUOTE_LENGTH {
+			quote = append(quote[:MAX_QUOTE_LENGTH], '…')
+		}
+
+		attachments = append(attachments, SlackAttachment{
+			Title:      review.Title,
+			TitleLink:  review.Permalink,
+			AuthorName: review.Author,
+			Text:       string(quote),
+			Fallback:   review.Message + " " + review.Author,
+			Color:      "danger",
+			Fields: []SlackAttachmentField{
+				{
+					Title: "Rating",
+					Value: review.Rate,
+					Short: true,
+				},
+			},
+			Footer: review.Store,
+		})
+	}
+
+	log.Printf("Posting digest of %s, %d new reviews", config.Key(), total)
+
+	return SendSlackPayload(config.DigestWebHookUri, SlackPayload{
+		UserName:    config.BotName,
+		IconEmoji:   config.IconEmoji,
+		Text:        config.Key() + " Competitor Digest:",
+		Attachments: attachments,
+	})
+}
+
+// ratingDistribution renders review counts per rating, five stars first.
+func ratingDistribution(ratings map[int]int) string {
+	lines := []string{}
+	for rating := 5; rating >= 1; rating-- {
+		if count := ratings[rating]; count > 0 {
+			lines = append(lines, fmt.Sprintf("%s %d", parseAppStoreRate(rating), count))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// recommendationSplit renders Steam review counts as Steam renders them.
+func recommendationSplit(recommended int, notRecommended int) string {
+	lines := []string{}
+	if recommended > 0 {
+		lines = append(lines, fmt.Sprintf("%s %d", parseSteamRate(true), recommended))
+	}
+	if notRecommended > 0 {
+		lines = append(lines, fmt.Sprintf("%s %d", parseSteamRate(false), notRecommended))
+	}
+	return strings.Join(lines, "\n")
+}
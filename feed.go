@@ -0,0 +1,327 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FEED_SOURCE_NAME = "Feed"
+	// FEED_MAX_ID_LENGTH is the size of stored review ids, longer entry ids
+	// are hashed.
+	FEED_MAX_ID_LENGTH = 255
+)
+
+// FEED_DEFAULT_FIELDS are the elements tried for every review field of RSS
+// and Atom entries when the feed has no mapping for it.
+var FEED_DEFAULT_FIELDS = FeedFields{
+	Id:     "guid | id | link | link@href",
+	Author: "author/name | author | dc:creator",
+	Title:  "title",
+	Body:   "content:encoded | content | description | summary",
+	Date:   "updated | published | pubDate | dc:date",
+	Link:   "link@href | link",
+}
+
+// FEED_PUBLISHED_FIELDS are the elements of the first publication date of
+// an entry, which stays the same when it is edited.
+const FEED_PUBLISHED_FIELDS = "published | pubDate | dc:date"
+
+// FEED_DATE_LAYOUTS are the date formats of RSS and Atom feeds.
+var FEED_DATE_LAYOUTS = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+var feedClient = &http.Client{Timeout: 30 * time.Second}
+
+// FeedConfig is an RSS or Atom feed watched as a review store.
+type FeedConfig struct {
+	// Name is the store name of the feed reviews, it is required and unique
+	// per app.
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Fields maps review fields to entry elements.
+	Fields FeedFields `yaml:"fields"`
+}
+
+// FeedFields maps review fields to paths of entry elements. A path names
+// nested elements separated by slashes, such as "author/name", and may end
+// with an attribute like "link@href". Alternatives are separated by "|", the
+// first one found is used. Namespace prefixes such as "dc:" are optional.
+type FeedFields struct {
+	Id     string `yaml:"id"`
+	Author string `yaml:"author"`
+	Title  string `yaml:"title"`
+	Body   string `yaml:"body"`
+	// Rating is read as a number of stars out of 5, feeds without ratings
+	// leave it unset.
+	Rating string `yaml:"rating"`
+	Date   string `yaml:"date"`
+	Link   string `yaml:"link"`
+}
+
+func init() {
+	RegisterSources(FEED_SOURCE_NAME, NewFeedSources)
+}
+
+type FeedSource struct {
+	config AppConfig
+	feed   FeedConfig
+}
+
+// NewFeedSources returns a source for every feed of app.
+func NewFeedSources(config AppConfig) []ReviewSource {
+	sources := []ReviewSource{}
+	for _, feed := range config.Feeds {
+		if feed.URL == "" {
+			log.Printf("Feed %s of %s has no url", feed.Name, config.Key())
+			continue
+		}
+		sources = append(sources, &FeedSource{config, feed})
+	}
+	return sources
+}
+
+func (s *FeedSource) Name() string {
+	return s.feed.Name
+}
+
+func (s *FeedSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{}
+}
+
+// Fetch reads the feed, entries are not necessarily in date order so they
+// are sorted before looking for known reviews.
+func (s *FeedSource) Fetch(since time.Time) (Reviews, error) {
+	log.Println(s.feed.URL)
+
+	res, err := feedClient.Get(s.feed.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded %s", s.feed.URL, res.Status)
+	}
+
+	var root feedNode
+	if err := xml.NewDecoder(res.Body).Decode(&root); err != nil {
+		return nil, fmt.Errorf("decoding feed %s failed: %v", s.feed.URL, err)
+	}
+
+	reviews, parseErrors := ParseFeed(s.feed, &root)
+	reviews, _, err = TakeUnseen(s.config.Key(), reviews, since)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
+
+// feedNode is an XML element of any feed.
+type feedNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Content  string     `xml:",chardata"`
+	Children []feedNode `xml:",any"`
+}
+
+// ParseFeed maps the items of an RSS feed, or the entries of an Atom feed.
+func ParseFeed(feed FeedConfig, root *feedNode) (Reviews, ParseErrors) {
+	entries := []*feedNode{}
+	root.walk(func(node *feedNode) bool {
+		if node.XMLName.Local == "item" || node.XMLName.Local == "entry" {
+			entries = append(entries, node)
+			return false
+		}
+		return true
+	})
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for i, entry := range entries {
+		review, err := parseFeedEntry(feed, entry)
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{
+				Source: feed.Name,
+				Entry:  i,
+				Err:    err,
+			})
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	sort.Sort(reviews)
+	return reviews, parseErrors
+}
+
+func parseFeedEntry(feed FeedConfig, entry *feedNode) (Review, error) {
+	field := func(mapping string, fallback string) string {
+		if mapping == "" {
+			mapping = fallback
+		}
+		return entry.field(mapping)
+	}
+
+	fields := feed.Fields
+	body := field(fields.Body, FEED_DEFAULT_FIELDS.Body)
+	title := field(fields.Title, FEED_DEFAULT_FIELDS.Title)
+	link := field(fields.Link, FEED_DEFAULT_FIELDS.Link)
+
+	author := field(fields.Author, FEED_DEFAULT_FIELDS.Author)
+	dateText := field(fields.Date, FEED_DEFAULT_FIELDS.Date)
+
+	id := field(fields.Id, FEED_DEFAULT_FIELDS.Id)
+	if id == "" {
+		// entries without any id are told apart by fields kept across edits,
+		// the publication date rather than the updated one
+		published := entry.field(FEED_PUBLISHED_FIELDS)
+		id = feedHash(link + "\x00" + author + "\x00" + published)
+	} else if len(id) > FEED_MAX_ID_LENGTH {
+		id = feedHash(id)
+	}
+
+	date, err := parseFeedDate(dateText)
+	if err != nil {
+		return Review{}, fmt.Errorf("entry %s: %v", id, err)
+	}
+
+	review := Review{
+		ExternalID: id,
+		Author:     author,
+		Store:      feed.Name,
+		Title:      title,
+		Message:    body,
+		UpdatedAt:  date,
+		Permalink:  link,
+	}
+	if len(review.Title) == 0 {
+		review.Title = "No title provided"
+	}
+
+	if fields.Rating != "" {
+		ratingText := entry.field(fields.Rating)
+		rating, err := strconv.ParseFloat(ratingText, 64)
+		rate := int(rating + 0.5)
+		if err != nil || rate < 1 || rate > 5 {
+			return Review{}, fmt.Errorf("invalid rating %q of entry %s", ratingText, id)
+		}
+		review.Rating = rate
+		review.Rate = parseAppStoreRate(rate)
+	}
+
+	return review, nil
+}
+
+func feedHash(text string) string {
+	hash := sha1.Sum([]byte(text))
+	return hex.EncodeToString(hash[:])
+}
+
+func parseFeedDate(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, fmt.Errorf("missing date")
+	}
+	for _, layout := range FEED_DATE_LAYOUTS {
+		if date, err := time.Parse(layout, text); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format %q", text)
+}
+
+// walk calls visit on the node and its descendants, children of a node are
+// skipped when visit returns false.
+func (node *feedNode) walk(visit func(node *feedNode) bool) {
+	if !visit(node) {
+		return
+	}
+	for i := range node.Children {
+		node.Children[i].walk(visit)
+	}
+}
+
+// field returns the trimmed text of the first alternative of mapping found
+// in the entry.
+func (node *feedNode) field(mapping string) string {
+	for _, path := range strings.Split(mapping, "|") {
+		if value, ok := node.lookup(strings.TrimSpace(path)); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func (node *feedNode) lookup(path string) (string, bool) {
+	attr := ""
+	if i := strings.Index(path, "@"); i >= 0 {
+		path, attr = path[:i], path[i+1:]
+	}
+
+	current := node
+	if path != "" {
+		for _, name := range strings.Split(path, "/") {
+			child := current.child(name)
+			if child == nil {
+				return "", false
+			}
+			current = child
+		}
+	}
+
+	if attr != "" {
+		for _, a := range current.Attrs {
+			if feedNameMatches(a.Name, attr) {
+				return strings.TrimSpace(a.Value), true
+			}
+		}
+		return "", false
+	}
+	return strings.TrimSpace(current.Content), true
+}
+
+func (node *feedNode) child(name string) *feedNode {
+	for i := range node.Children {
+		if feedNameMatches(node.Children[i].XMLName, name) {
+			return &node.Children[i]
+		}
+	}
+	return nil
+}
+
+// feedNameMatches compares an element name to a mapping name, the prefix of
+// a mapping name like "dc:creator" must match the end of the namespace uri
+// as the prefix itself is not kept by the decoder.
+func feedNameMatches(name xml.Name, mapping string) bool {
+	prefix, local := "", mapping
+	if i := strings.Index(mapping, ":"); i >= 0 {
+		prefix, local = mapping[:i], mapping[i+1:]
+	}
+	if name.Local != local {
+		return false
+	}
+	if prefix == "" {
+		return true
+	}
+	return name.Space == prefix || strings.Contains(name.Space, "/"+prefix)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadFeedFixture(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func parseFeedFixture(t *testing.T, feed FeedConfig, data []byte) (Reviews, ParseErrors) {
+	var root feedNode
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&root); err != nil {
+		t.Fatal(err)
+	}
+	return ParseFeed(feed, &root)
+}
+
+func TestParseFeed(t *testing.T) {
+	for _, test := range []struct {
+		fixture  string
+		fields   FeedFields
+		skipped  int
+		expected Reviews
+	}{
+		{
+			fixture: "feed_rss.xml",
+			skipped: 1,
+			expected: Reviews{
+				{
+					ExternalID: "forum.example.com-topic-1042",
+					Author:     "jane_doe",
+					Title:      "Sync stopped working",
+					Message:    "<p>Since version 4.1 my notes no longer sync.</p>",
+					UpdatedAt:  time.Date(2023, 1, 2, 10, 30, 0, 0, time.UTC),
+					Permalink:  "https://forum.example.com/t/sync-stopped-working/1042",
+				},
+				{
+					ExternalID: "https://forum.example.com/t/dark-mode-please/1041",
+					Author:     "max",
+					Title:      "Dark mode please",
+					Message:    "Would love a dark theme.",
+					UpdatedAt:  time.Date(2023, 1, 1, 8, 0, 0, 0, time.UTC),
+					Permalink:  "https://forum.example.com/t/dark-mode-please/1041",
+				},
+			},
+		},
+		{
+			fixture: "feed_atom.xml",
+			expected: Reviews{
+				{
+					ExternalID: "tag:reviews.example.com,2023:981",
+					Author:     "Jane Doe",
+					Title:      "Great widgets",
+					Message:    "The home screen widgets are great.",
+					UpdatedAt:  time.Date(2023, 1, 3, 9, 0, 0, 0, time.UTC),
+					Permalink:  "https://reviews.example.com/r/981",
+				},
+				{
+					ExternalID: feedHash("\x00Max Mustermann\x002023-01-01T07:15:00+01:00"),
+					Author:     "Max Mustermann",
+					Title:      "Crashes on start",
+					Message:    "It closes right after the splash screen.",
+					UpdatedAt:  time.Date(2023, 1, 1, 6, 15, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			fixture: "feed_custom.xml",
+			fields: FeedFields{
+				Id:     "review@id",
+				Author: "review/reviewer/name",
+				Body:   "review/text",
+				Rating: "review/stars",
+				Date:   "review/date",
+			},
+			skipped: 1,
+			expected: Reviews{
+				{
+					ExternalID: "r-77",
+					Author:     "Ana Silva",
+					Title:      "Quase perfeito",
+					Message:    "Muito bom, mas falta sincronização.",
+					Rating:     5,
+					UpdatedAt:  time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC),
+					Permalink:  "https://reviews.example.com/r/77",
+				},
+			},
+		},
+	} {
+		feed := FeedConfig{Name: "Forum", URL: "https://forum.example.com/feed", Fields: test.fields}
+		reviews, parseErrors := parseFeedFixture(t, feed, loadFeedFixture(t, test.fixture))
+
+		if len(parseErrors) != test.skipped {
+			t.Errorf("%s: %d entries skipped, want %d: %v", test.fixture, len(parseErrors), test.skipped, parseErrors)
+		}
+		if len(reviews) != len(test.expected) {
+			t.Fatalf("%s: %d reviews, want %d", test.fixture, len(reviews), len(test.expected))
+		}
+
+		for i, want := range test.expected {
+			got := reviews[i]
+			if got.ExternalID != want.ExternalID || got.Author != want.Author || got.Title != want.Title ||
+				got.Message != want.Message || got.Rating != want.Rating ||
+				!got.UpdatedAt.Equal(want.UpdatedAt) || got.Permalink != want.Permalink {
+				t.Errorf("%s: review %d is %+v, want %+v", test.fixture, i, got, want)
+			}
+			if got.Store != feed.Name {
+				t.Errorf("%s: review %d has store %q, want %q", test.fixture, i, got.Store, feed.Name)
+			}
+		}
+	}
+}
+
+func TestParseFeedIdKeptAcrossEdits(t *testing.T) {
+	feed := FeedConfig{Name: "Reviews", URL: "https://reviews.example.com/feed"}
+	data := loadFeedFixture(t, "feed_atom.xml")
+	edited := strings.Replace(string(data),
+		"<updated>2023-01-01T07:15:00+01:00</updated>",
+		"<updated>2023-01-04T18:00:00+01:00</updated>", 1)
+	edited = strings.Replace(edited,
+		"It closes right after the splash screen.",
+		"Fixed in the latest update, thanks!", 1)
+	if edited == string(data) {
+		t.Fatal("fixture was not edited")
+	}
+
+	before, _ := parseFeedFixture(t, feed, data)
+	after, _ := parseFeedFixture(t, feed, []byte(edited))
+	if len(before) != 2 || len(after) != 2 {
+		t.Fatalf("%d and %d reviews, want 2", len(before), len(after))
+	}
+
+	// the edited entry moves to the front as it is now the latest one
+	if after[0].ExternalID != before[1].ExternalID {
+		t.Errorf("edited entry id %q, want %q", after[0].ExternalID, before[1].ExternalID)
+	}
+	if after[0].Message != "Fixed in the latest update, thanks!" {
+		t.Errorf("edited entry message %q", after[0].Message)
+	}
+}
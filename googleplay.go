@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	GOOGLE_PLAY_NAME              = "Google Play"
+	GOOGLE_PLAY_BASE_URI          = "https://play.google.com"
+	GOOGLE_PLAY_BATCH_EXECUTE_URI = GOOGLE_PLAY_BASE_URI + "/_/PlayStoreUi/data/batchexecute"
+	GOOGLE_PLAY_REVIEWS_RPC       = "UsvDTd"
+	GOOGLE_PLAY_PAGE_SIZE         = 40
+	GOOGLE_PLAY_MAX_PAGES         = 10
+
+	GOOGLE_PLAY_SORT_RELEVANCE = "relevance"
+	GOOGLE_PLAY_SORT_NEWEST    = "newest"
+	GOOGLE_PLAY_SORT_RATING    = "rating"
+)
+
+// googlePlaySortOrders maps sort options to the sort ids of the reviews rpc.
+var googlePlaySortOrders = map[string]int{
+	GOOGLE_PLAY_SORT_RELEVANCE: 1,
+	GOOGLE_PLAY_SORT_NEWEST:    2,
+	GOOGLE_PLAY_SORT_RATING:    3,
+}
+
+// GooglePlayLocale is a language and country pair of Play Store reviews.
+type GooglePlayLocale struct {
+	Language string `yaml:"hl"`
+	Country  string `yaml:"gl"`
+}
+
+// Key identifies the language and country pair in watermarks, as fr_CA.
+func (locale GooglePlayLocale) Key() string {
+	return locale.Language + "_" + strings.ToUpper(locale.Country)
+}
+
+// PlayLocales returns the configured language and country pairs, or the
+// Google Play location alone.
+func (app AppConfig) PlayLocales() []GooglePlayLocale {
+	if len(app.GooglePlayLocales) > 0 {
+		return app.GooglePlayLocales
+	}
+	return []GooglePlayLocale{{Language: app.GooglePlayLocation}}
+}
+
+var googlePlayClient = &http.Client{Timeout: 30 * time.Second}
+
+func init() {
+	RegisterSource(GOOGLE_PLAY_NAME, NewGooglePlaySource)
+}
+
+type GooglePlaySource struct {
+	config AppConfig
+	// watermarks holds the newest review of every language and country
+	// pair fetched by the last Fetch.
+	watermarks map[string]time.Time
+}
+
+func NewGooglePlaySource(config AppConfig) ReviewSource {
+	// the Developer API source takes over when a service account is configured
+	if config.GooglePlayAppId == "" || config.GooglePlayServiceAccount != "" {
+		return nil
+	}
+	return &GooglePlaySource{config: config}
+}
+
+func (s *GooglePlaySource) Name() string {
+	return GOOGLE_PLAY_NAME
+}
+
+func (s *GooglePlaySource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true, Countries: len(s.config.GooglePlayLocales) > 0, DeveloperResponses: true}
+}
+
+// googlePlayRatings reads the overall rating of every configured language and
+// country from the Play Store listing.
+func googlePlayRatings(config AppConfig) ([]RatingSummary, error) {
+	summaries := []RatingSummary{}
+	for _, locale := range config.PlayLocales() {
+		metadata, err := GetGooglePlayMetadata(config, locale)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, RatingSummary{
+			Store:   GOOGLE_PLAY_NAME,
+			Country: locale.Country,
+			Average: metadata.AverageRating,
+			Count:   metadata.RatingCount,
+		})
+	}
+	return summaries, nil
+}
+
+func (s *GooglePlaySource) RatingSummaries() ([]RatingSummary, error) {
+	return googlePlayRatings(s.config)
+}
+
+// Fetch walks the reviews of every configured language and country in turn,
+// reviews found in several of them are kept once, tagged with the first one.
+func (s *GooglePlaySource) Fetch(since time.Time) (Reviews, error) {
+	locales := s.config.PlayLocales()
+	tagged := len(s.config.GooglePlayLocales) > 0
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	seen := map[string]bool{}
+	s.watermarks = map[string]time.Time{}
+	for _, locale := range locales {
+		localeSince := since
+		// pairs lag independently, as App Store storefronts do, several
+		// languages of a country are kept apart
+		if locale.Country != "" && !backfilling {
+			var err error
+			localeSince, err = dbh.Watermark(s.config.Key(), GOOGLE_PLAY_NAME, locale.Key())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		localeReviews, localeErrors, err := s.fetchLocale(locale, localeSince)
+		if err != nil {
+			return nil, err
+		}
+		parseErrors = append(parseErrors, localeErrors...)
+
+		for _, review := range localeReviews {
+			// reviews kept with an earlier pair still move this one forward
+			if locale.Country != "" && review.UpdatedAt.After(s.watermarks[locale.Key()]) {
+				s.watermarks[locale.Key()] = review.UpdatedAt
+			}
+
+			if seen[review.ExternalID] {
+				continue
+			}
+			seen[review.ExternalID] = true
+
+			if tagged {
+				review.Language = locale.Language
+				review.Country = locale.Country
+			}
+			reviews = append(reviews, review)
+		}
+	}
+
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
+
+// Watermarks returns the newest review of every language and country pair
+// walked by the last Fetch.
+func (s *GooglePlaySource) Watermarks() map[string]time.Time {
+	return s.watermarks
+}
+
+// fetchLocale walks review pages of a language and country until a known
+// review or one older than since is reached. Pages are not in date order
+// unless sorted by newest, every page is walked then and older reviews are
+// filtered out.
+func (s *GooglePlaySource) fetchLocale(locale GooglePlayLocale, since time.Time) (Reviews, ParseErrors, error) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	token := ""
+	for page := 1; page <= MaxPages(GOOGLE_PLAY_MAX_PAGES); page++ {
+		pageReviews, pageErrors, next, err := GetGooglePlayReviews(s.config, locale.Language, locale.Country, token)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pageError := range pageErrors {
+			pageError.Page = page
+			pageError.Country = locale.Country
+		}
+		parseErrors = append(parseErrors, pageErrors...)
+
+		if s.config.GooglePlaySort == GOOGLE_PLAY_SORT_NEWEST {
+			unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+			if err != nil {
+				return nil, nil, err
+			}
+			reviews = append(reviews, unseen...)
+			if done {
+				break
+			}
+		} else {
+			reviews = append(reviews, pageReviews.Since(since)...)
+		}
+
+		if next == "" {
+			break
+		}
+		token = next
+	}
+
+	return reviews, parseErrors, nil
+}
+
+// GetGooglePlayReviews returns a page of reviews through the reviews rpc of the
+// Play Store web front end in the hl language and gl country, and the token of
+// the next page if any. An empty gl leaves the country to the store.
+func GetGooglePlayReviews(config AppConfig, hl string, gl string, token string) (Reviews, ParseErrors, string, error) {
+	log.Println(fmt.Sprintf("id: %s, hl: %s, gl: %s", config.GooglePlayAppId, hl, gl))
+
+	query := url.Values{}
+	query.Add("hl", hl)
+	if gl != "" {
+		query.Add("gl", gl)
+	}
+	uri := GOOGLE_PLAY_BATCH_EXECUTE_URI + "?" + query.Encode()
+
+	payload, err := BatchExecute(googlePlayClient, uri, GOOGLE_PLAY_REVIEWS_RPC, googlePlayReviewsArgs(config, token))
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, nil, "", fmt.Errorf("decoding Google Play reviews failed: %v", err)
+	}
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	entries, _ := JSONValue(data, 0).([]interface{})
+	for i, entry := range entries {
+		review, err := parseGooglePlayEntry(config, entry)
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{
+				Source: GOOGLE_PLAY_NAME,
+				Entry:  i,
+				Err:    err,
+			})
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	sort.Sort(reviews)
+	return reviews, parseErrors, JSONString(data, 1, 1), nil
+}
+
+// googlePlayReviewsArgs builds the reviews rpc arguments:
+// [null, null, [2, sort, [count, null, token], null, [null, rating]], [app id, 7]]
+func googlePlayReviewsArgs(config AppConfig, token string) []interface{} {
+	var pageToken, rating interface{}
+	if token != "" {
+		pageToken = token
+	}
+	if config.GooglePlayRating > 0 {
+		rating = config.GooglePlayRating
+	}
+
+	sortOrder, ok := googlePlaySortOrders[config.GooglePlaySort]
+	if !ok {
+		sortOrder = googlePlaySortOrders[GOOGLE_PLAY_SORT_NEWEST]
+	}
+
+	return []interface{}{
+		nil,
+		nil,
+		[]interface{}{2, sortOrder, []interface{}{GOOGLE_PLAY_PAGE_SIZE, nil, pageToken}, nil, []interface{}{nil, rating}},
+		[]interface{}{config.GooglePlayAppId, 7},
+	}
+}
+
+// parseGooglePlayEntry maps a review of the reviews rpc, laid out as
+// [id, [author, ...], rating, null, text, [seconds, nanos], thumbs up,
+// [null, reply, [seconds, nanos]], ..., version].
+func parseGooglePlayEntry(config AppConfig, entry interface{}) (Review, error) {
+	id := JSONString(entry, 0)
+	if id == "" {
+		return Review{}, fmt.Errorf("missing review id")
+	}
+
+	rate := int(JSONInt(entry, 2))
+	if rate < 1 || rate > 5 {
+		return Review{}, fmt.Errorf("invalid rating %v of review %s", JSONValue(entry, 2), id)
+	}
+
+	seconds := JSONInt(entry, 5, 0)
+	if seconds == 0 {
+		return Review{}, fmt.Errorf("missing date of review %s", id)
+	}
+
+	query := url.Values{}
+	query.Add("id", config.GooglePlayAppId)
+	query.Add("reviewId", id)
+
+	return Review{
+		ExternalID: id,
+		Author:     JSONString(entry, 1, 0),
+		Store:      GOOGLE_PLAY_NAME,
+		Title:      "No title provided",
+		Message:    JSONString(entry, 4),
+		Rating:     rate,
+		Rate:       parseAppStoreRate(rate),
+		UpdatedAt:  googlePlayTime(seconds),
+		Permalink:  GOOGLE_PLAY_BASE_URI + "/store/apps/details?" + query.Encode(),
+		AppVersion: JSONString(entry, 10),
+
+		DeveloperResponse:   JSONString(entry, 7, 1),
+		DeveloperResponseAt: googlePlayTime(JSONInt(entry, 7, 2, 0)),
+	}, nil
+}
+
+func googlePlayTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	GOOGLE_PLAY_API_SOURCE_NAME = "Google Play Developer API"
+	GOOGLE_PLAY_API_BASE_URI    = "https://androidpublisher.googleapis.com"
+	GOOGLE_PLAY_API_SCOPE       = "https://www.googleapis.com/auth/androidpublisher"
+	GOOGLE_PLAY_API_PAGE_SIZE   = 100
+	GOOGLE_PLAY_API_MAX_PAGES   = 50
+	JWT_BEARER_GRANT_TYPE       = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+)
+
+func init() {
+	RegisterSource(GOOGLE_PLAY_API_SOURCE_NAME, NewGooglePlayAPISource)
+}
+
+// GoogleServiceAccount is the JSON key of a Google Cloud service account.
+type GoogleServiceAccount struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyId string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenUri     string `json:"token_uri"`
+}
+
+// GooglePlayAPISource fetches reviews through the Play Developer API, it
+// replaces the web front end source when a service account is configured.
+type GooglePlayAPISource struct {
+	config  AppConfig
+	account GoogleServiceAccount
+	key     *rsa.PrivateKey
+	client  *http.Client
+}
+
+func NewGooglePlayAPISource(config AppConfig) ReviewSource {
+	if config.GooglePlayAppId == "" || config.GooglePlayServiceAccount == "" {
+		return nil
+	}
+
+	account, key, err := LoadGoogleServiceAccount(config.GooglePlayServiceAccount)
+	if err != nil {
+		log.Printf("Google Play service account of %s: %v", config.Key(), err)
+		return nil
+	}
+
+	return &GooglePlayAPISource{
+		config:  config,
+		account: account,
+		key:     key,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// LoadGoogleServiceAccount reads a service account key, given either as a
+// path to the JSON file or as the JSON itself.
+func LoadGoogleServiceAccount(keyOrPath string) (GoogleServiceAccount, *rsa.PrivateKey, error) {
+	var account GoogleServiceAccount
+
+	data := []byte(keyOrPath)
+	if !strings.HasPrefix(strings.TrimSpace(keyOrPath), "{") {
+		var err error
+		data, err = ioutil.ReadFile(keyOrPath)
+		if err != nil {
+			return account, nil, err
+		}
+	}
+
+	if err := json.Unmarshal(data, &account); err != nil {
+		return account, nil, err
+	}
+	if account.ClientEmail == "" || account.TokenUri == "" {
+		return account, nil, fmt.Errorf("client_email and token_uri are required")
+	}
+
+	parsed, err := ParsePKCS8PrivateKey([]byte(account.PrivateKey))
+	if err != nil {
+		return account, nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return account, nil, fmt.Errorf("private key is not a RSA key")
+	}
+
+	return account, key, nil
+}
+
+func (s *GooglePlayAPISource) Name() string {
+	return GOOGLE_PLAY_NAME
+}
+
+func (s *GooglePlayAPISource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true, DeveloperResponses: true}
+}
+
+func (s *GooglePlayAPISource) RatingSummaries() ([]RatingSummary, error) {
+	return googlePlayRatings(s.config)
+}
+
+// Fetch walks the last modified first review pages until one reaches a
+// review modified at or before since.
+func (s *GooglePlayAPISource) Fetch(since time.Time) (Reviews, error) {
+	token, err := s.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	pageToken := ""
+	for page := 1; page <= MaxPages(GOOGLE_PLAY_API_MAX_PAGES); page++ {
+		var list googlePlayAPIReviews
+		err := s.get(token, pageToken, &list)
+		if err != nil {
+			return nil, err
+		}
+
+		reached := false
+		for i, entry := range list.Reviews {
+			review, err := parseGooglePlayAPIReview(s.config, entry)
+			if err != nil {
+				parseErrors = append(parseErrors, &ParseError{
+					Source: GOOGLE_PLAY_API_SOURCE_NAME,
+					Page:   page,
+					Entry:  i,
+					Err:    err,
+				})
+				continue
+			}
+			reached = reached || !review.UpdatedAt.After(since)
+			reviews = append(reviews, review)
+		}
+
+		pageToken = list.TokenPagination.NextPageToken
+		if reached || pageToken == "" {
+			break
+		}
+	}
+
+	reviews = reviews.Since(since)
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
+
+// accessToken exchanges a JWT signed by the service account for an OAuth access token.
+func (s *GooglePlayAPISource) accessToken() (string, error) {
+	now := time.Now()
+	assertion, err := SignJWT(
+		map[string]string{"alg": "RS256", "typ": "JWT", "kid": s.account.PrivateKeyId},
+		map[string]interface{}{
+			"iss":   s.account.ClientEmail,
+			"scope": GOOGLE_PLAY_API_SCOPE,
+			"aud":   s.account.TokenUri,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		},
+		RS256Signer(s.key),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Add("grant_type", JWT_BEARER_GRANT_TYPE)
+	form.Add("assertion", assertion)
+
+	res, err := s.client.PostForm(s.account.TokenUri, form)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("decoding token response failed: %v", err)
+	}
+	if res.StatusCode != http.StatusOK || token.AccessToken == "" {
+		return "", fmt.Errorf("Google OAuth token request failed: %s %s %s", res.Status, token.Error, token.ErrorDescription)
+	}
+
+	return token.AccessToken, nil
+}
+
+func (s *GooglePlayAPISource) get(token string, pageToken string, v interface{}) error {
+	query := url.Values{}
+	query.Add("maxResults", strconv.Itoa(GOOGLE_PLAY_API_PAGE_SIZE))
+	if pageToken != "" {
+		query.Add("token", pageToken)
+	}
+	uri := fmt.Sprintf("%s/androidpublisher/v3/applications/%s/reviews?%s", s.config.GooglePlayAPIURI, url.PathEscape(s.config.GooglePlayAppId), query.Encode())
+	log.Println(uri)
+
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("%s responded %s: %s", uri, res.Status, body)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+type googlePlayAPIReviews struct {
+	Reviews         []googlePlayAPIReview `json:"reviews"`
+	TokenPagination struct {
+		NextPageToken string `json:"nextPageToken"`
+	} `json:"tokenPagination"`
+}
+
+type googlePlayAPIReview struct {
+	ReviewId   string `json:"reviewId"`
+	AuthorName string `json:"authorName"`
+	Comments   []struct {
+		UserComment      *googlePlayAPIUserComment      `json:"userComment"`
+		DeveloperComment *googlePlayAPIDeveloperComment `json:"developerComment"`
+	} `json:"comments"`
+}
+
+type googlePlayAPIUserComment struct {
+	Text             string                 `json:"text"`
+	LastModified     googlePlayAPITimestamp `json:"lastModified"`
+	StarRating       int                    `json:"starRating"`
+	ReviewerLanguage string                 `json:"reviewerLanguage"`
+	Device           string                 `json:"device"`
+	AndroidOsVersion int                    `json:"androidOsVersion"`
+	AppVersionCode   int                    `json:"appVersionCode"`
+	AppVersionName   string                 `json:"appVersionName"`
+	DeviceMetadata   struct {
+		ProductName  string `json:"productName"`
+		Manufacturer string `json:"manufacturer"`
+	} `json:"deviceMetadata"`
+}
+
+type googlePlayAPIDeveloperComment struct {
+	Text         string                 `json:"text"`
+	LastModified googlePlayAPITimestamp `json:"lastModified"`
+}
+
+type googlePlayAPITimestamp struct {
+	Seconds string `json:"seconds"`
+	Nanos   int64  `json:"nanos"`
+}
+
+func (t googlePlayAPITimestamp) Time() (time.Time, error) {
+	seconds, err := strconv.ParseInt(t.Seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", t.Seconds)
+	}
+	return time.Unix(seconds, t.Nanos), nil
+}
+
+func parseGooglePlayAPIReview(config AppConfig, entry googlePlayAPIReview) (Review, error) {
+	var user *googlePlayAPIUserComment
+	var developer *googlePlayAPIDeveloperComment
+	for _, comment := range entry.Comments {
+		if comment.UserComment != nil {
+			user = comment.UserComment
+		}
+		if comment.DeveloperComment != nil {
+			developer = comment.DeveloperComment
+		}
+	}
+	if user == nil {
+		return Review{}, fmt.Errorf("review %s has no user comment", entry.ReviewId)
+	}
+	if user.StarRating < 1 || user.StarRating > 5 {
+		return Review{}, fmt.Errorf("invalid rating %d of review %s", user.StarRating, entry.ReviewId)
+	}
+
+	updatedAt, err := user.LastModified.Time()
+	if err != nil {
+		return Review{}, err
+	}
+
+	// titled reviews are rendered as "title\tbody"
+	title := "No title provided"
+	message := strings.TrimSpace(user.Text)
+	if parts := strings.SplitN(message, "\t", 2); len(parts) == 2 {
+		if parts[0] != "" {
+			title = parts[0]
+		}
+		message = parts[1]
+	}
+
+	device := user.DeviceMetadata.ProductName
+	if device == "" {
+		device = user.Device
+	}
+
+	appVersion := user.AppVersionName
+	if user.AppVersionCode != 0 {
+		appVersion = strings.TrimSpace(fmt.Sprintf("%s (%d)", appVersion, user.AppVersionCode))
+	}
+
+	osVersion := ""
+	if user.AndroidOsVersion != 0 {
+		osVersion = fmt.Sprintf("Android API %d", user.AndroidOsVersion)
+	}
+
+	query := url.Values{}
+	query.Add("id", config.GooglePlayAppId)
+	query.Add("reviewId", entry.ReviewId)
+
+	review := Review{
+		ExternalID: entry.ReviewId,
+		Author:     entry.AuthorName,
+		Store:      GOOGLE_PLAY_NAME,
+		Title:      title,
+		Message:    message,
+		Rating:     user.StarRating,
+		Rate:       parseAppStoreRate(user.StarRating),
+		UpdatedAt:  updatedAt,
+		Permalink:  GOOGLE_PLAY_BASE_URI + "/store/apps/details?" + query.Encode(),
+		AppVersion: appVersion,
+		Language:   user.ReviewerLanguage,
+		Device:     device,
+		OSVersion:  osVersion,
+	}
+
+	if developer != nil {
+		review.DeveloperResponse = developer.Text
+		review.DeveloperResponseAt, _ = developer.LastModified.Time()
+	}
+
+	return review, nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const googlePlayAPITestReviews = `{
+  "reviews": [
+    {
+      "reviewId": "gp:AOqpTOH1",
+      "authorName": "Jane Doe",
+      "comments": [
+        {"userComment": {
+          "text": "Great update\tThe new widget is exactly what I needed.",
+          "lastModified": {"seconds": "1672740000", "nanos": 0},
+          "starRating": 5,
+          "reviewerLanguage": "en",
+          "device": "walleye",
+          "androidOsVersion": 33,
+          "appVersionCode": 412,
+          "appVersionName": "4.1.2",
+          "deviceMetadata": {"productName": "Pixel 2", "manufacturer": "Google"}
+        }},
+        {"developerComment": {
+          "text": "Thanks Jane!",
+          "lastModified": {"seconds": "1672750000", "nanos": 0}
+        }}
+      ]
+    },
+    {
+      "reviewId": "gp:AOqpTOH2",
+      "authorName": "Max Mustermann",
+      "comments": [
+        {"userComment": {
+          "text": "Stürzt beim Start ab.",
+          "lastModified": {"seconds": "1672653600", "nanos": 0},
+          "starRating": 1,
+          "reviewerLanguage": "de",
+          "device": "a52q"
+        }}
+      ]
+    }
+  ],
+  "tokenPagination": {"nextPageToken": "page-2"}
+}`
+
+const googlePlayAPITestOlderReviews = `{
+  "reviews": [
+    {
+      "reviewId": "gp:AOqpTOH3",
+      "authorName": "Someone",
+      "comments": [
+        {"userComment": {
+          "text": "Works.",
+          "lastModified": {"seconds": "1672567200", "nanos": 0},
+          "starRating": 4
+        }}
+      ]
+    },
+    {
+      "reviewId": "gp:AOqpTOH4",
+      "authorName": "Someone Else",
+      "comments": [
+        {"userComment": {
+          "text": "Old review.",
+          "lastModified": {"seconds": "1672480800", "nanos": 0},
+          "starRating": 3
+        }}
+      ]
+    }
+  ],
+  "tokenPagination": {"nextPageToken": "page-3"}
+}`
+
+func TestGooglePlayAPISource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	pages := []string{}
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if grantType := r.FormValue("grant_type"); grantType != JWT_BEARER_GRANT_TYPE {
+			t.Errorf("grant type is %q", grantType)
+		}
+
+		segments := strings.Split(r.FormValue("assertion"), ".")
+		if len(segments) != 3 {
+			t.Errorf("assertion %q is not a JWT", r.FormValue("assertion"))
+			http.Error(w, "invalid assertion", http.StatusBadRequest)
+			return
+		}
+		digest := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+		signature, _ := base64.RawURLEncoding.DecodeString(segments[2])
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			t.Errorf("assertion signature is invalid: %v", err)
+		}
+
+		var claims map[string]interface{}
+		data, _ := base64.RawURLEncoding.DecodeString(segments[1])
+		if err := json.Unmarshal(data, &claims); err != nil {
+			t.Errorf("decoding assertion claims failed: %v", err)
+		}
+		if claims["iss"] != "jonsnow@example.iam.gserviceaccount.com" || claims["scope"] != GOOGLE_PLAY_API_SCOPE || claims["aud"] != server.URL+"/token" {
+			t.Errorf("assertion claims are %v", claims)
+		}
+
+		fmt.Fprint(w, `{"access_token": "test-token", "token_type": "Bearer", "expires_in": 3600}`)
+	})
+
+	mux.HandleFunc("/androidpublisher/v3/applications/com.example.app/reviews", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-token" {
+			t.Errorf("authorization is %q", auth)
+		}
+		if maxResults := r.FormValue("maxResults"); maxResults != "100" {
+			t.Errorf("maxResults is %q", maxResults)
+		}
+
+		token := r.FormValue("token")
+		pages = append(pages, token)
+		switch token {
+		case "":
+			fmt.Fprint(w, googlePlayAPITestReviews)
+		case "page-2":
+			fmt.Fprint(w, googlePlayAPITestOlderReviews)
+		default:
+			http.Error(w, "unexpected page", http.StatusBadRequest)
+		}
+	})
+
+	account, err := json.Marshal(GoogleServiceAccount{
+		ClientEmail:  "jonsnow@example.iam.gserviceaccount.com",
+		PrivateKeyId: "test-key",
+		PrivateKey:   string(privateKey),
+		TokenUri:     server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewGooglePlayAPISource(AppConfig{
+		GooglePlayAppId:          "com.example.app",
+		GooglePlayServiceAccount: string(account),
+		GooglePlayAPIURI:         server.URL,
+	})
+	if source == nil {
+		t.Fatal("source is not configured")
+	}
+
+	reviews, err := source.Fetch(time.Unix(1672567200, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second page reaches the watermark, the third one is not requested
+	if strings.Join(pages, ",") != ",page-2" {
+		t.Errorf("requested pages are %q", pages)
+	}
+	if len(reviews) != 3 {
+		t.Fatalf("%d reviews, want 3", len(reviews))
+	}
+
+	review := reviews[0]
+	if review.ExternalID != "gp:AOqpTOH1" || review.Author != "Jane Doe" || review.Store != GOOGLE_PLAY_NAME || review.Rating != 5 {
+		t.Errorf("first review is %+v", review)
+	}
+	if review.Title != "Great update" || review.Message != "The new widget is exactly what I needed." {
+		t.Errorf("first review reads %q %q", review.Title, review.Message)
+	}
+	if review.AppVersion != "4.1.2 (412)" || review.Device != "Pixel 2" || review.OSVersion != "Android API 33" || review.Language != "en" {
+		t.Errorf("first review metadata is %q %q %q %q", review.AppVersion, review.Device, review.OSVersion, review.Language)
+	}
+	if !review.UpdatedAt.Equal(time.Unix(1672740000, 0)) {
+		t.Errorf("first review is dated %v", review.UpdatedAt)
+	}
+	if review.DeveloperResponse != "Thanks Jane!" || !review.DeveloperResponseAt.Equal(time.Unix(1672750000, 0)) {
+		t.Errorf("first review response is %q at %v", review.DeveloperResponse, review.DeveloperResponseAt)
+	}
+
+	review = reviews[1]
+	if review.ExternalID != "gp:AOqpTOH2" || review.Title != "No title provided" || review.Device != "a52q" || review.DeveloperResponse != "" {
+		t.Errorf("second review is %+v", review)
+	}
+
+	if reviews[2].ExternalID != "gp:AOqpTOH3" {
+		t.Errorf("third review is %s, the one at the watermark", reviews[2].ExternalID)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HUAWEI_NAME           = "Huawei AppGallery"
+	HUAWEI_API_URI        = "https://web-drcn.hispace.dbankcloud.cn/uowap/index"
+	HUAWEI_APP_URI        = "https://appgallery.huawei.com/app/"
+	HUAWEI_COMMENT_METHOD = "internal.user.commenList3"
+	HUAWEI_DEFAULT_LOCALE = "zh_CN"
+	HUAWEI_PAGE_SIZE      = 25
+	HUAWEI_MAX_PAGES      = 10
+)
+
+var (
+	huaweiClient  = &http.Client{Timeout: 30 * time.Second}
+	chinaStandard = time.FixedZone("CST", 8*60*60)
+)
+
+func init() {
+	RegisterSource(HUAWEI_NAME, NewHuaweiSource)
+}
+
+type HuaweiSource struct {
+	config AppConfig
+}
+
+func NewHuaweiSource(config AppConfig) ReviewSource {
+	if config.HuaweiAppId == "" {
+		return nil
+	}
+	return &HuaweiSource{config}
+}
+
+func (s *HuaweiSource) Name() string {
+	return HUAWEI_NAME
+}
+
+func (s *HuaweiSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true}
+}
+
+type huaweiComments struct {
+	TotalPages int             `json:"totalPages"`
+	List       []huaweiComment `json:"list"`
+}
+
+type huaweiComment struct {
+	CommentId   string      `json:"commentId"`
+	Id          string      `json:"id"`
+	NickName    string      `json:"nickName"`
+	Rating      json.Number `json:"rating"`
+	CommentInfo string      `json:"commentInfo"`
+	OperTime    string      `json:"operTime"`
+	VersionName string      `json:"versionName"`
+	Phone       string      `json:"phone"`
+}
+
+// Fetch walks the newest first comment pages until a known review or one
+// older than since is reached.
+func (s *HuaweiSource) Fetch(since time.Time) (Reviews, error) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for page := 1; page <= MaxPages(HUAWEI_MAX_PAGES); page++ {
+		pageReviews, pageErrors, totalPages, err := GetHuaweiReviews(s.config, page)
+		if err != nil {
+			return nil, err
+		}
+		parseErrors = append(parseErrors, pageErrors...)
+
+		unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, unseen...)
+		if done || page >= totalPages {
+			break
+		}
+	}
+
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
+
+// GetHuaweiReviews returns a page of reviews from the comment list API of the
+// AppGallery web store, and the number of pages.
+func GetHuaweiReviews(config AppConfig, page int) (Reviews, ParseErrors, int, error) {
+	appId := huaweiAppId(config.HuaweiAppId)
+
+	query := url.Values{}
+	query.Add("method", HUAWEI_COMMENT_METHOD)
+	query.Add("serviceType", "20")
+	query.Add("reqPageNum", strconv.Itoa(page))
+	query.Add("maxResults", strconv.Itoa(HUAWEI_PAGE_SIZE))
+	query.Add("appid", appId)
+	query.Add("version", "10.0.0")
+	query.Add("locale", config.HuaweiLocale)
+	uri := HUAWEI_API_URI + "?" + query.Encode()
+	log.Println(uri)
+
+	res, err := huaweiClient.Get(uri)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, 0, fmt.Errorf("%s responded %s", uri, res.Status)
+	}
+
+	var comments huaweiComments
+	if err := json.NewDecoder(res.Body).Decode(&comments); err != nil {
+		return nil, nil, 0, fmt.Errorf("decoding AppGallery comments failed: %v", err)
+	}
+
+	reviews, parseErrors := ParseHuaweiComments(appId, config.HuaweiLocale, comments)
+	for _, parseError := range parseErrors {
+		parseError.Page = page
+	}
+	return reviews, parseErrors, comments.TotalPages, nil
+}
+
+// ParseHuaweiComments maps a page of comments of appId written for locale.
+func ParseHuaweiComments(appId string, locale string, comments huaweiComments) (Reviews, ParseErrors) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for i, comment := range comments.List {
+		review, err := parseHuaweiComment(appId, locale, comment)
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{
+				Source: HUAWEI_NAME,
+				Entry:  i,
+				Err:    err,
+			})
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	sort.Sort(reviews)
+	return reviews, parseErrors
+}
+
+func parseHuaweiComment(appId string, locale string, comment huaweiComment) (Review, error) {
+	id := comment.CommentId
+	if id == "" {
+		id = comment.Id
+	}
+	if id == "" {
+		return Review{}, fmt.Errorf("missing comment id")
+	}
+
+	rating, err := comment.Rating.Float64()
+	rate := int(rating + 0.5)
+	if err != nil || rate < 1 || rate > 5 {
+		return Review{}, fmt.Errorf("invalid rating %q of comment %s", comment.Rating, id)
+	}
+
+	date, err := ParseHuaweiDate(locale, comment.OperTime)
+	if err != nil {
+		return Review{}, fmt.Errorf("comment %s: %v", id, err)
+	}
+
+	return Review{
+		ExternalID: id,
+		Author:     comment.NickName,
+		Store:      HUAWEI_NAME,
+		Title:      "No title provided",
+		Message:    strings.TrimSpace(comment.CommentInfo),
+		Rating:     rate,
+		Rate:       parseAppStoreRate(rate),
+		UpdatedAt:  date,
+		Permalink:  HUAWEI_APP_URI + appId + "?commentId=" + url.QueryEscape(id),
+		AppVersion: comment.VersionName,
+		Device:     comment.Phone,
+	}, nil
+}
+
+// ParseHuaweiDate parses a comment date of the locale, AppGallery writes
+// them in China Standard Time.
+func ParseHuaweiDate(locale string, text string) (time.Time, error) {
+	return ParseLocalDate(locale, text, chinaStandard)
+}
+
+// huaweiAppId accepts app ids with or without their C prefix, as in C100123456.
+func huaweiAppId(id string) string {
+	if strings.HasPrefix(id, "C") {
+		return id
+	}
+	return "C" + id
+}
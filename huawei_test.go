@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseHuaweiComments(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/huawei_comments.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var comments huaweiComments
+	if err := json.Unmarshal(fixture, &comments); err != nil {
+		t.Fatal(err)
+	}
+	if comments.TotalPages != 4 {
+		t.Errorf("%d pages, want 4", comments.TotalPages)
+	}
+
+	appId := huaweiAppId("100123456")
+	reviews, parseErrors := ParseHuaweiComments(appId, HUAWEI_DEFAULT_LOCALE, comments)
+	if len(parseErrors) != 1 || parseErrors[0].Entry != 2 {
+		t.Errorf("skipped entries are %v, want entry 2", parseErrors)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("%d reviews, want 2", len(reviews))
+	}
+
+	review := reviews[0]
+	if review.ExternalID != "3f1c2e7a9b8d4c6e" || review.Author != "华为用户" || review.Store != HUAWEI_NAME ||
+		review.Message != "很好用，同步很快。" || review.Rating != 5 || review.Rate != parseAppStoreRate(5) ||
+		review.AppVersion != "4.1.0" || review.Device != "HUAWEI Mate 40 Pro" {
+		t.Errorf("first review is %+v", review)
+	}
+	if want := time.Date(2023, 1, 2, 15, 4, 5, 0, chinaStandard); !review.UpdatedAt.Equal(want) {
+		t.Errorf("first review is dated %v, want %v", review.UpdatedAt, want)
+	}
+	if review.Permalink != "https://appgallery.huawei.com/app/C100123456?commentId=3f1c2e7a9b8d4c6e" {
+		t.Errorf("first review permalink is %s", review.Permalink)
+	}
+
+	// comments without a comment id fall back to their id, ratings may be numbers
+	review = reviews[1]
+	if review.ExternalID != "1179980" || review.Rating != 2 || review.Message != "更新后经常闪退" {
+		t.Errorf("second review is %+v", review)
+	}
+	if want := time.Date(2022, 12, 31, 8, 30, 0, 0, chinaStandard); !review.UpdatedAt.Equal(want) {
+		t.Errorf("second review is dated %v, want %v", review.UpdatedAt, want)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+)
+
+// SignJWT encodes header and claims into a compact JWT signed by sign.
+func SignJWT(header interface{}, claims interface{}, sign func(data []byte) ([]byte, error)) (string, error) {
+	encodedHeader, err := encodeJWTSegment(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := encodeJWTSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := encodedHeader + "." + encodedClaims
+	signature, err := sign([]byte(unsigned))
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func encodeJWTSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// RS256Signer signs JWTs with key, as expected by Google OAuth.
+func RS256Signer(key *rsa.PrivateKey) func(data []byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		digest := sha256.Sum256(data)
+		return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	}
+}
+
+// ES256Signer signs JWTs with key, as expected by App Store Connect. The
+// signature is the fixed size concatenation of r and s.
+func ES256Signer(key *ecdsa.PrivateKey) func(data []byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		digest := sha256.Sum256(data)
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			return nil, err
+		}
+
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*size)
+		rBytes, sBytes := r.Bytes(), s.Bytes()
+		copy(signature[size-len(rBytes):size], rBytes)
+		copy(signature[2*size-len(sBytes):], sBytes)
+		return signature, nil
+	}
+}
+
+// ParsePKCS8PrivateKey decodes the PEM encoded PKCS #8 private key used by
+// Google service accounts and App Store Connect API keys.
+func ParsePKCS8PrivateKey(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
@@ -6,24 +6,27 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"html"
 	"io/ioutil"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
-	"sort"
-	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/PuerkitoBio/goquery"
-	"github.com/clbanning/mxj"
 	"github.com/lib/pq"
 	"gopkg.in/yaml.v2"
 )
 
+// Config holds the monitored apps. Top level app settings are the defaults
+// of every entry in Apps, and describe the only app when Apps is empty.
 type Config struct {
+	AppConfig `yaml:",inline"`
+	Apps      []AppConfig `yaml:"apps"`
+}
+
+type AppConfig struct {
+	Name               string `yaml:"name"`
 	GooglePlayAppId    string `yaml:"google_play_app_id"`
 	AppStoreAppId      string `yaml:"app_store_app_id"`
 	ReviewCount        int    `yaml:"review_count"`
@@ -31,20 +34,95 @@ type Config struct {
 	IconEmoji          string `yaml:"icon_emoji"`
 	WebHookUri         string `yaml:"web_hook_uri"`
 	GooglePlayLocation string `yaml:"google_play_location"`
-	AppStoreLocation   string `yaml:"app_store_location"`
-	AppStoreURI        string
+	// GooglePlaySort is one of newest, rating or relevance, GooglePlayRating
+	// only fetches reviews with that many stars when set.
+	GooglePlaySort   string `yaml:"google_play_sort"`
+	GooglePlayRating int    `yaml:"google_play_rating"`
+	// GooglePlayLocales lists language and country pairs fetched in turn,
+	// GooglePlayLocation alone is fetched when empty.
+	GooglePlayLocales []GooglePlayLocale `yaml:"google_play_locales"`
+	// GooglePlayServiceAccount is the path of a service account JSON key, or
+	// the key itself, enabling the Play Developer API source.
+	GooglePlayServiceAccount string `yaml:"google_play_service_account"`
+	GooglePlayAPIURI         string `yaml:"google_play_api_uri"`
+	AppStoreLocation         string `yaml:"app_store_location"`
+	// AppStoreLocations lists storefront countries, "all" walks every storefront.
+	AppStoreLocations   []string `yaml:"app_store_locations"`
+	AppStoreConcurrency int      `yaml:"app_store_concurrency"`
+	AppStoreURI         string   `yaml:"-"`
+	GooglePlayURI       string   `yaml:"-"`
+	// App Store Connect API key, AppStoreConnectKey is the .p8 file path or
+	// its content. Reviews are fetched through the API when all are set.
+	AppStoreConnectKeyId    string `yaml:"app_store_connect_key_id"`
+	AppStoreConnectIssuerId string `yaml:"app_store_connect_issuer_id"`
+	AppStoreConnectKey      string `yaml:"app_store_connect_key"`
+	AppStoreConnectAPIURI   string `yaml:"app_store_connect_api_uri"`
+	// TestFlightFeedback posts beta feedback of the App Store app, it
+	// requires the App Store Connect API key.
+	TestFlightFeedback bool `yaml:"testflight_feedback"`
+	// AmazonMarketplace is the marketplace domain, amazon.com by default.
+	AmazonASIN        string `yaml:"amazon_asin"`
+	AmazonMarketplace string `yaml:"amazon_marketplace"`
+	// HuaweiLocale is the locale of AppGallery comments, zh_CN by default.
+	HuaweiAppId  string `yaml:"huawei_app_id"`
+	HuaweiLocale string `yaml:"huawei_locale"`
+	// MicrosoftStoreMarket is the two letters market of reviews, US by default.
+	MicrosoftStoreProductId string `yaml:"microsoft_store_product_id"`
+	MicrosoftStoreMarket    string `yaml:"microsoft_store_market"`
+	// SteamLanguage is a Steam language name such as english, SteamPurchaseType
+	// is steam or non_steam_purchase, both default to all.
+	SteamAppId        string `yaml:"steam_app_id"`
+	SteamLanguage     string `yaml:"steam_language"`
+	SteamPurchaseType string `yaml:"steam_purchase_type"`
+	// ChromeWebStoreLocale is the hl language of the store, en by default.
+	ChromeWebStoreItemId string `yaml:"chrome_web_store_item_id"`
+	ChromeWebStoreLocale string `yaml:"chrome_web_store_locale"`
+	// TrackMetadata snapshots the store listings and posts their changes.
+	TrackMetadata bool `yaml:"track_metadata"`
+	// Competitor apps are stored without posting their reviews, a digest is
+	// posted to DigestWebHookUri every DigestEvery instead, such as "168h".
+	Competitor       bool   `yaml:"competitor"`
+	DigestWebHookUri string `yaml:"digest_web_hook_uri"`
+	DigestEvery      string `yaml:"digest_every"`
+	// Feeds are RSS or Atom feeds watched as review stores.
+	Feeds []FeedConfig `yaml:"feeds"`
 }
 
 type Review struct {
-	Id        int
-	Store     string
-	Author    string
-	Title     string
-	Message   string
-	Rate      string
-	UpdatedAt time.Time `meddler:"updated_at,localtime"`
-	Permalink string
-	Color     string
+	Id  int
+	App string
+	// ExternalID is the review id given by the store.
+	ExternalID string
+	Store      string
+	Country    string
+	Author     string
+	Title      string
+	Message    string
+	Rating     int
+	Rate       string
+	UpdatedAt  time.Time `meddler:"updated_at,localtime"`
+	Permalink  string
+	// AuthorLink is the profile page of the author, when the store has one.
+	AuthorLink string
+	Color      string
+
+	AppVersion string
+	Language   string
+	Device     string
+	OSVersion  string
+
+	DeveloperResponse   string
+	DeveloperResponseAt time.Time
+
+	// HelpfulVotes and UnhelpfulVotes count readers who rated the review.
+	HelpfulVotes   int
+	UnhelpfulVotes int
+
+	// Screenshots are image urls attached to beta feedback.
+	Screenshots []string
+
+	// StoredHash is the content hash kept in the review table.
+	StoredHash string
 }
 
 type Reviews []Review
@@ -70,6 +148,7 @@ type SlackAttachment struct {
 	AuthorName string                 `json:"author_name"`
 	Footer     string                 `json:"footer"`
 	Fields     []SlackAttachmentField `json:"fields"`
+	ImageURL   string                 `json:"image_url,omitempty"`
 }
 
 type SlackAttachmentField struct {
@@ -79,20 +158,17 @@ type SlackAttachmentField struct {
 }
 
 const (
-	TABLE_NAME                  = "review"
-	GOOGLE_PLAY_BASE_URI        = "https://play.google.com/store/getreviews"
-	APP_STORE_BASE_URI          = "https://itunes.apple.com"
-	REVIEW_CLASS_NAME           = ".single-review"
-	AUTHOR_NAME_CLASS_NAME      = ".review-info span.author-name"
-	REVIEW_DATE_CLASS_NAME      = ".review-info .review-date"
-	REVIEW_TITLE_CLASS_NAME     = ".review-body .review-title"
-	REVIEW_MESSAGE_CLASS_NAME   = ".review-body"
-	REVIEW_LINK_CLASS_NAME      = ".review-link"
-	REVIEW_RATE_CLASS_NAME      = ".review-info-star-rating .current-rating"
-	RATING_EMOJI                = ":star:"
-	RATING_EMOJI_2              = ":star2:"
-	MAX_REVIEW_NUM              = 40
-	REVIEW_PERMALINK_CLASS_NAME = ".review-info .reviews-permalink"
+	TABLE_NAME               = "review"
+	WATERMARK_TABLE_NAME     = "watermark"
+	REVISION_TABLE_NAME      = "review_revision"
+	RATING_CHANGE_TABLE_NAME = "rating_change"
+	DIGEST_TABLE_NAME        = "digest"
+	RATING_EMOJI             = ":star:"
+	RATING_EMOJI_2           = ":star2:"
+	MAX_REVIEW_NUM           = 40
+
+	APP_STORE_CONCURRENCY = 4
+	APP_STORE_MAX_PAGES   = 10
 )
 
 var (
@@ -119,6 +195,129 @@ func (dbh *DBH) LastInsertId(tableName string) int {
 	return id
 }
 
+// Watermark returns the stored watermark of app on store, narrowed to a
+// storefront when country is set, or to a language and country pair keyed
+// as fr_CA. Sources never saved a watermark fall back to their latest stored
+// review, including reviews stored before apps and countries were tracked.
+func (dbh *DBH) Watermark(app string, store string, country string) (time.Time, error) {
+	var watermark pq.NullTime
+	row := dbh.QueryRow(`SELECT updated_at FROM `+WATERMARK_TABLE_NAME+` WHERE app = $1 AND store = $2 AND country = $3`, app, store, country)
+	err := row.Scan(&watermark)
+	if err == nil {
+		return watermark.Time, nil
+	}
+	if err != sql.ErrNoRows {
+		return time.Time{}, err
+	}
+
+	query := `SELECT MAX(updated_at) FROM ` + TABLE_NAME + ` WHERE (app = $1 OR app = '') AND store = $2`
+	args := []interface{}{app, store}
+	// reviews keep the language and country of a pair apart
+	if i := strings.Index(country, "_"); i >= 0 {
+		query += ` AND (LOWER(country) = $3 OR country = '') AND (language = $4 OR language = '')`
+		args = append(args, strings.ToLower(country[i+1:]), country[:i])
+	} else if country != "" {
+		query += ` AND (country = $3 OR country = '')`
+		args = append(args, country)
+	}
+	if err := dbh.QueryRow(query, args...).Scan(&watermark); err != nil {
+		return time.Time{}, err
+	}
+
+	return watermark.Time, nil
+}
+
+// SetWatermark moves the watermark of app on store forward to t.
+func (dbh *DBH) SetWatermark(app string, store string, country string, t time.Time) error {
+	_, err := dbh.Exec(`INSERT INTO `+WATERMARK_TABLE_NAME+` (app, store, country, updated_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (app, store, country) DO UPDATE SET updated_at = GREATEST(`+WATERMARK_TABLE_NAME+`.updated_at, EXCLUDED.updated_at)`,
+		app, store, country, t)
+	return err
+}
+
+// FindReview returns the stored state of review, nil when it was never seen.
+// Reviews are matched by their store native id, or by permalink for sources
+// without one. Rows stored before native ids were kept, such as App Store
+// RSS reviews which kept the author profile as permalink, are matched by
+// that link and their date so other reviews of the author stay new.
+func (dbh *DBH) FindReview(review Review) (*Review, error) {
+	// reviews stored before apps were tracked have an empty app
+	query := `SELECT id, rating, title, message, content_hash, response, response_at FROM review WHERE `
+	if review.ExternalID == "" {
+		return scanStoredReview(review, dbh.QueryRow(query+`comment_uri = $1 AND (app = $2 OR app = '')`, review.Permalink, review.App))
+	}
+
+	stored, err := scanStoredReview(review, dbh.QueryRow(query+`store = $1 AND (app = $2 OR app = '') AND external_id = $3`,
+		review.Store, review.App, review.ExternalID))
+	if stored != nil || err != nil {
+		return stored, err
+	}
+
+	return scanStoredReview(review, dbh.QueryRow(query+`store = $1 AND (app = $2 OR app = '') AND external_id IS NULL
+		AND (comment_uri = $3 OR comment_uri = $4) AND updated_at = $5::date ORDER BY id LIMIT 1`,
+		review.Store, review.App, review.Permalink, review.AuthorLink, review.UpdatedAt))
+}
+
+// scanStoredReview reads a row selected by FindReview over review, nil when
+// there is none.
+func scanStoredReview(review Review, row *sql.Row) (*Review, error) {
+	stored := review
+	var responseAt pq.NullTime
+	err := row.Scan(&stored.Id, &stored.Rating, &stored.Title, &stored.Message, &stored.StoredHash, &stored.DeveloperResponse, &responseAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stored.DeveloperResponseAt = responseAt.Time
+
+	stored.Rate = StoredRate(stored.Store, stored.Rating)
+	return &stored, nil
+}
+
+// StoredRate renders a stored rating as the source of store renders it,
+// Steam ratings stand for recommendations.
+func StoredRate(store string, rating int) string {
+	if store == STEAM_NAME {
+		return parseSteamRate(rating == STEAM_RECOMMENDED_RATING)
+	}
+	return parseAppStoreRate(rating)
+}
+
+// MarkNotified records that reviews were posted, or deliberately never will be.
+func (dbh *DBH) MarkNotified(reviews Reviews) error {
+	for _, review := range reviews {
+		_, err := dbh.Exec(`UPDATE review SET notified = TRUE WHERE id = $1`, review.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncate cuts s to at most n bytes without splitting a character, to fit
+// VARCHAR columns.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// NullString stores empty strings as NULL.
+func NullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// NullTime stores zero times as NULL.
+func NullTime(t time.Time) pq.NullTime {
+	return pq.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
 func NewConfig(path string) (config Config, err error) {
 	config = Config{}
 
@@ -131,10 +330,6 @@ func NewConfig(path string) (config Config, err error) {
 		return config, err
 	}
 
-	if config.ReviewCount > MAX_REVIEW_NUM || config.ReviewCount < 1 {
-		return config, fmt.Errorf("Please Set Num Between 1 and 40.")
-	}
-
 	url := os.Getenv("DATABASE_URL")
 	fmt.Println(url)
 	connection, _ := pq.ParseURL(url)
@@ -182,24 +377,109 @@ func NewConfig(path string) (config Config, err error) {
 		config.GooglePlayLocation = googlePlayLocation
 	}
 
-	// override Location if environment variable found
+	// override service account if environment variable found, the JSON key itself is expected
+	googlePlayServiceAccount := os.Getenv("JON_SNOW_GOOGLE_PLAY_SERVICE_ACCOUNT")
+	if googlePlayServiceAccount != "" {
+		config.GooglePlayServiceAccount = googlePlayServiceAccount
+	}
+
+	// override App Store Connect API key if environment variables found, the .p8 content is expected
+	appStoreConnectKeyId := os.Getenv("JON_SNOW_APP_STORE_CONNECT_KEY_ID")
+	if appStoreConnectKeyId != "" {
+		config.AppStoreConnectKeyId = appStoreConnectKeyId
+	}
+	appStoreConnectIssuerId := os.Getenv("JON_SNOW_APP_STORE_CONNECT_ISSUER_ID")
+	if appStoreConnectIssuerId != "" {
+		config.AppStoreConnectIssuerId = appStoreConnectIssuerId
+	}
+	appStoreConnectKey := os.Getenv("JON_SNOW_APP_STORE_CONNECT_KEY")
+	if appStoreConnectKey != "" {
+		config.AppStoreConnectKey = appStoreConnectKey
+	}
+
+	// override Location if environment variable found, comma separated countries or "all" are accepted
 	appStoreLocation := os.Getenv("JON_SNOW_APP_STORE_LOCATION")
 	if appStoreLocation != "" {
 		config.AppStoreLocation = appStoreLocation
 	}
 
-	if config.AppStoreAppId == "" && config.GooglePlayAppId == "" {
-		return config, fmt.Errorf("At least one of Google Play or App Store app id is required.")
+	if len(config.Apps) == 0 {
+		config.Apps = []AppConfig{config.AppConfig}
 	}
 
-	appStoreURI := ""
-	if id := config.AppStoreAppId; id != "" {
-		appStoreURI = fmt.Sprintf("%s/%s/app/id%s", APP_STORE_BASE_URI, config.AppStoreLocation, id)
-		config.AppStoreURI = appStoreURI
+	names := map[string]bool{}
+	uris := []string{}
+	for i := range config.Apps {
+		app := &config.Apps[i]
+		app.inherit(config.AppConfig)
+
+		if app.ReviewCount > MAX_REVIEW_NUM || app.ReviewCount < 1 {
+			return config, fmt.Errorf("Please Set Num Between 1 and 40.")
+		}
+
+		if len(app.storeIds()) == 0 {
+			return config, fmt.Errorf("At least one store app id is required.")
+		}
+
+		if _, ok := googlePlaySortOrders[app.GooglePlaySort]; !ok {
+			return config, fmt.Errorf("Google Play sort of %s should be one of newest, rating or relevance.", app.Key())
+		}
+
+		if app.GooglePlayRating < 0 || app.GooglePlayRating > 5 {
+			return config, fmt.Errorf("Google Play rating of %s should be between 1 and 5.", app.Key())
+		}
+
+		if app.Competitor && app.DigestWebHookUri == "" {
+			return config, fmt.Errorf("Competitor app %s requires a digest web hook uri.", app.Key())
+		}
+
+		if app.DigestEvery != "" {
+			if _, err := time.ParseDuration(app.DigestEvery); err != nil {
+				return config, fmt.Errorf("Digest interval of %s should be a duration such as 24h.", app.Key())
+			}
+		}
+
+		if names[app.Key()] {
+			return config, fmt.Errorf("App %s is configured twice.", app.Key())
+		}
+		names[app.Key()] = true
+
+		// feed names are their store names, they key watermarks and reviews
+		feedNames := map[string]bool{}
+		for _, feed := range app.Feeds {
+			if feed.URL == app.Key() {
+				return config, fmt.Errorf("App watching feeds only requires a name.")
+			}
+			if feed.Name == "" {
+				return config, fmt.Errorf("Feed %s of %s requires a name.", feed.URL, app.Key())
+			}
+			if feedNames[feed.Name] {
+				return config, fmt.Errorf("Feed %s of %s is configured twice.", feed.Name, app.Key())
+			}
+			feedNames[feed.Name] = true
+		}
+
+		if id := app.AppStoreAppId; id != "" {
+			storefronts := app.AppStoreStorefronts()
+			if len(storefronts) == 0 {
+				return config, fmt.Errorf("App Store location of %s is required.", app.Key())
+			}
+
+			app.AppStoreURI = fmt.Sprintf("%s/%s/app/id%s", APP_STORE_BASE_URI, app.PrimaryStorefront(), id)
+			uris = append(uris, app.AppStoreURI)
+		}
+
+		// Google Play listings are only read when metadata is tracked, the
+		// checked page is kept for the tracker
+		if id := app.GooglePlayAppId; id != "" && app.TrackMetadata {
+			app.GooglePlayURI = GooglePlayListingURI(id, app.GooglePlayLocation, "")
+			if _, err := StoreListing(app.GooglePlayURI); err != nil {
+				log.Println(err)
+			}
+		}
 	}
 
-	ids := []string{appStoreURI}
-	err = CheckStoreURLAvailable(ids)
+	err = CheckStoreURLAvailable(uris)
 	if err != nil {
 		return config, err
 	}
@@ -207,9 +487,178 @@ func NewConfig(path string) (config Config, err error) {
 	return config, err
 }
 
+// inherit fills unset settings from defaults.
+func (app *AppConfig) inherit(defaults AppConfig) {
+	if app.ReviewCount == 0 {
+		app.ReviewCount = defaults.ReviewCount
+	}
+	if app.BotName == "" {
+		app.BotName = defaults.BotName
+	}
+	if app.IconEmoji == "" {
+		app.IconEmoji = defaults.IconEmoji
+	}
+	if app.WebHookUri == "" {
+		app.WebHookUri = defaults.WebHookUri
+	}
+	if app.GooglePlayLocation == "" {
+		app.GooglePlayLocation = defaults.GooglePlayLocation
+	}
+	if app.GooglePlaySort == "" {
+		app.GooglePlaySort = defaults.GooglePlaySort
+	}
+	if app.GooglePlaySort == "" {
+		app.GooglePlaySort = GOOGLE_PLAY_SORT_NEWEST
+	}
+	if app.GooglePlayRating == 0 {
+		app.GooglePlayRating = defaults.GooglePlayRating
+	}
+	if len(app.GooglePlayLocales) == 0 {
+		app.GooglePlayLocales = defaults.GooglePlayLocales
+	}
+	if !app.TrackMetadata {
+		app.TrackMetadata = defaults.TrackMetadata
+	}
+	if app.DigestWebHookUri == "" {
+		app.DigestWebHookUri = defaults.DigestWebHookUri
+	}
+	if app.DigestEvery == "" {
+		app.DigestEvery = defaults.DigestEvery
+	}
+	if app.GooglePlayServiceAccount == "" {
+		app.GooglePlayServiceAccount = defaults.GooglePlayServiceAccount
+	}
+	if app.GooglePlayAPIURI == "" {
+		app.GooglePlayAPIURI = defaults.GooglePlayAPIURI
+	}
+	if app.GooglePlayAPIURI == "" {
+		app.GooglePlayAPIURI = GOOGLE_PLAY_API_BASE_URI
+	}
+	if app.AppStoreLocation == "" {
+		app.AppStoreLocation = defaults.AppStoreLocation
+	}
+	if len(app.AppStoreLocations) == 0 {
+		app.AppStoreLocations = defaults.AppStoreLocations
+	}
+	if app.AppStoreConcurrency == 0 {
+		app.AppStoreConcurrency = defaults.AppStoreConcurrency
+	}
+	if app.AppStoreConnectKeyId == "" {
+		app.AppStoreConnectKeyId = defaults.AppStoreConnectKeyId
+	}
+	if app.AppStoreConnectIssuerId == "" {
+		app.AppStoreConnectIssuerId = defaults.AppStoreConnectIssuerId
+	}
+	if app.AppStoreConnectKey == "" {
+		app.AppStoreConnectKey = defaults.AppStoreConnectKey
+	}
+	if app.AppStoreConnectAPIURI == "" {
+		app.AppStoreConnectAPIURI = defaults.AppStoreConnectAPIURI
+	}
+	if app.AppStoreConnectAPIURI == "" {
+		app.AppStoreConnectAPIURI = APP_STORE_CONNECT_BASE_URI
+	}
+	if !app.TestFlightFeedback {
+		app.TestFlightFeedback = defaults.TestFlightFeedback
+	}
+	if app.AmazonMarketplace == "" {
+		app.AmazonMarketplace = defaults.AmazonMarketplace
+	}
+	if app.HuaweiLocale == "" {
+		app.HuaweiLocale = defaults.HuaweiLocale
+	}
+	if app.HuaweiLocale == "" {
+		app.HuaweiLocale = HUAWEI_DEFAULT_LOCALE
+	}
+	if app.MicrosoftStoreMarket == "" {
+		app.MicrosoftStoreMarket = defaults.MicrosoftStoreMarket
+	}
+	if app.MicrosoftStoreMarket == "" {
+		app.MicrosoftStoreMarket = MICROSOFT_STORE_DEFAULT_MARKET
+	}
+	if app.SteamLanguage == "" {
+		app.SteamLanguage = defaults.SteamLanguage
+	}
+	if app.SteamLanguage == "" {
+		app.SteamLanguage = STEAM_DEFAULT_LANGUAGE
+	}
+	if app.SteamPurchaseType == "" {
+		app.SteamPurchaseType = defaults.SteamPurchaseType
+	}
+	if app.SteamPurchaseType == "" {
+		app.SteamPurchaseType = STEAM_DEFAULT_PURCHASE_TYPE
+	}
+	if app.ChromeWebStoreLocale == "" {
+		app.ChromeWebStoreLocale = defaults.ChromeWebStoreLocale
+	}
+	if app.ChromeWebStoreLocale == "" {
+		app.ChromeWebStoreLocale = CHROME_WEB_STORE_DEFAULT_LOCALE
+	}
+	app.GooglePlayLocation = NormalizeLocale(app.GooglePlayLocation)
+	locales := []GooglePlayLocale{}
+	for _, locale := range app.GooglePlayLocales {
+		locales = append(locales, GooglePlayLocale{NormalizeLocale(locale.Language), strings.ToLower(locale.Country)})
+	}
+	app.GooglePlayLocales = locales
+	app.HuaweiLocale = NormalizeLocale(app.HuaweiLocale)
+	app.ChromeWebStoreLocale = NormalizeLocale(app.ChromeWebStoreLocale)
+	if app.AppStoreConcurrency < 1 {
+		app.AppStoreConcurrency = APP_STORE_CONCURRENCY
+	}
+}
+
+// AppStoreStorefronts returns the App Store countries to fetch reviews from.
+func (app AppConfig) AppStoreStorefronts() []string {
+	if len(app.AppStoreLocations) > 0 {
+		return ParseStorefronts(strings.Join(app.AppStoreLocations, ","))
+	}
+	return ParseStorefronts(app.AppStoreLocation)
+}
+
+// PrimaryStorefront is the App Store country the listing of the app is read
+// from, us when it is among watched storefronts.
+func (app AppConfig) PrimaryStorefront() string {
+	storefronts := app.AppStoreStorefronts()
+	for _, country := range storefronts {
+		if country == "us" {
+			return country
+		}
+	}
+	return storefronts[0]
+}
+
+// Key identifies the app in the review table.
+func (app AppConfig) Key() string {
+	if app.Name != "" {
+		return app.Name
+	}
+	return app.storeIds()[0]
+}
+
+// storeIds lists the ids of app on every configured store.
+func (app AppConfig) storeIds() []string {
+	ids := []string{}
+	for _, id := range []string{app.GooglePlayAppId, app.AppStoreAppId, app.AmazonASIN, app.HuaweiAppId, app.MicrosoftStoreProductId, app.SteamAppId, app.ChromeWebStoreItemId} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	for _, feed := range app.Feeds {
+		if feed.URL != "" {
+			ids = append(ids, feed.URL)
+		}
+	}
+	return ids
+}
+
 func ValidateStoreURI(uri string) error {
 	res, err := http.Get(uri)
-	if err == nil && res.StatusCode == http.StatusNotFound {
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
 		err = fmt.Errorf("URI: %s is not exists", uri)
 	}
 	return err
@@ -243,290 +692,140 @@ func main() {
 		return
 	}
 
-	if config.GooglePlayAppId != "" {
-		err = ProcessGooglePlayReviews(config)
-
+	if flag.Arg(0) == "backfill" {
+		err = Backfill(config, flag.Args()[1:])
 		if err != nil {
 			log.Println(err)
-			return
 		}
+		return
 	}
 
-	if config.AppStoreURI != "" {
-		err = ProcessAppStoreReviews(config)
-
+	if flag.Arg(0) == "stats" {
+		err = Stats(config, flag.Args()[1:])
 		if err != nil {
 			log.Println(err)
-			return
 		}
+		return
 	}
 
-	log.Println("all done.")
-}
-
-func ProcessGooglePlayReviews(config Config) error {
-	log.Println("Processing Android reviews ...")
-
-	reviews, err := GetGooglePlayReviews(config, GOOGLE_PLAY_BASE_URI, config.GooglePlayAppId, config.GooglePlayLocation)
-	if err != nil {
-		return err
-	}
-
-	reviews, err = SaveReviews(reviews)
-	if err != nil {
-		return err
-	}
-
-	err = PostReview(config, reviews)
-	if err != nil {
-		return err
-	}
-
-	log.Println("Google Play reviews process finished")
-
-	return nil
-}
-
-func ProcessAppStoreReviews(config Config) error {
-	log.Println("Processing App Store reviews ...")
-
-	uri := config.AppStoreURI
+	for _, app := range config.Apps {
+		for _, source := range NewSources(app) {
+			// overall ratings are recorded first so notifications show their trend
+			if err := RecordRatings(app, source); err != nil {
+				log.Printf("%s ratings of %s: %v", source.Name(), app.Key(), err)
+			}
 
-	reviews, err := GetAppStoreReviews(config, uri)
-	if err != nil {
-		return err
-	}
+			// a failing store is retried on the next run, other sources and apps go on
+			err = ProcessReviews(app, source)
+			if err != nil {
+				log.Printf("%s reviews of %s: %v", source.Name(), app.Key(), err)
+				continue
+			}
+		}
 
-	reviews, err = SaveReviews(reviews)
-	if err != nil {
-		return err
-	}
+		// listings failing to load are retried on the next run, other apps go on
+		if app.TrackMetadata {
+			err = TrackMetadata(app)
+			if err != nil {
+				log.Println(err)
+			}
+		}
 
-	err = PostReview(config, reviews)
-	if err != nil {
-		return err
+		if app.Competitor {
+			err = PostDigestIfDue(app)
+			if err != nil {
+				log.Printf("digest of %s: %v", app.Key(), err)
+				continue
+			}
+		}
 	}
 
-	log.Println("App Store reviews process finished")
-
-	return nil
+	log.Println("all done.")
 }
 
-func escapedBytesToString(b []byte) string {
-	b = bytes.Replace(b, []byte("\\u003c"), []byte("<"), -1)
-	b = bytes.Replace(b, []byte("\\u003e"), []byte(">"), -1)
-	b = bytes.Replace(b, []byte("\\u0026"), []byte("&"), -1)
-	b = bytes.Replace(b, []byte("\\u003d"), []byte("="), -1)
-	b = bytes.Replace(b, []byte("\\\""), []byte("\""), -1)
-	return string(b)
+// SavedReviews sorts the reviews stored by SaveReviews by what should be notified.
+type SavedReviews struct {
+	// New reviews were never seen before.
+	New Reviews
+	// Changed reviews were edited since they were stored.
+	Changed ReviewChanges
+	// Responded reviews got a new or edited developer response.
+	Responded Reviews
 }
 
-func GetGooglePlayReviews(config Config, uri string, id string, hl string) (Reviews, error) {
-	log.Println(fmt.Sprintf("id: %s, hl: %s", id, hl))
-	hc := http.Client{}
-
-	form := url.Values{}
-	form.Add("hl", hl)
-	form.Add("id", id)
-	form.Add("reviewType", "0")
-	form.Add("pageNum", "0")
-	form.Add("reviewSortOrder", "0")
-	form.Add("xhr", "1")
-
-	req, err := http.NewRequest("POST", uri, strings.NewReader(form.Encode()))
-	req.PostForm = form
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+// SaveReviews stores reviews and reports which of them are new, edited or responded.
+func SaveReviews(reviews Reviews) (SavedReviews, error) {
+	saved := SavedReviews{Reviews{}, ReviewChanges{}, Reviews{}}
 
-	resp, err := hc.Do(req)
-
-	defer resp.Body.Close()
-
-	bodyBytes, _ := ioutil.ReadAll(resp.Body)
-	bodyString := escapedBytesToString(bodyBytes)
-	firstSpace := strings.Index(bodyString, " ")
-	lastSpace := strings.LastIndex(bodyString, " ")
-	content := html.UnescapeString(bodyString[firstSpace+1 : lastSpace])
-
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
-
-	if err != nil {
-		return nil, err
-	}
-
-	reviews := Reviews{}
-
-	doc.Find(REVIEW_CLASS_NAME).Each(func(i int, s *goquery.Selection) {
-		authorNode := s.Find(AUTHOR_NAME_CLASS_NAME)
-		authorName := authorNode.Text()
-		dateNode := s.Find(REVIEW_DATE_CLASS_NAME)
-
-		var timeForm string
-		if config.GooglePlayLocation == "zh_TW" || config.GooglePlayLocation == "zh_CN" {
-			timeForm = "2006年1月2日"
-		} else if config.GooglePlayLocation == "en" {
-			timeForm = "January 2, 2006"
-		}
-
-		date, err := time.Parse(timeForm, dateNode.Text())
+	for _, review := range reviews {
+		stored, err := dbh.FindReview(review)
 		if err != nil {
-			log.Println(err)
-			return
+			return saved, err
 		}
 
-		reviewPermalinkNode := s.Find(REVIEW_PERMALINK_CLASS_NAME)
-		reviewPermalink, _ := reviewPermalinkNode.Attr("href")
+		if stored == nil { // not exist
+			err := dbh.QueryRow(`INSERT INTO review (app, author, store, country, external_id, comment_uri, updated_at, app_version, language, device, os_version,
+				rating, title, message, content_hash, response, response_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id`,
+				review.App, truncate(review.Author, 255), review.Store, review.Country, NullString(review.ExternalID), truncate(review.Permalink, 255), review.UpdatedAt,
+				review.AppVersion, review.Language, review.Device, review.OSVersion,
+				review.Rating, review.Title, review.Message, review.ContentHash(),
+				review.DeveloperResponse, NullTime(review.DeveloperResponseAt)).Scan(&review.Id)
+			if err != nil {
+				return saved, err
+			}
 
-		reviewTitle := s.Find(REVIEW_TITLE_CLASS_NAME).Text()
-		if len(reviewTitle) == 0 {
-			reviewTitle = "No title provided"
+			if err := dbh.SaveRevision(review); err != nil {
+				return saved, err
+			}
+			saved.New = append(saved.New, review)
+			continue
 		}
 
-		reviewMessage := s.Find(REVIEW_MESSAGE_CLASS_NAME).Text()
-		reviewLink := s.Find(REVIEW_LINK_CLASS_NAME).Text()
+		review.Id = stored.Id
 
-		reviewMessage = strings.Split(reviewMessage, reviewLink)[0]
-
-		reviewRateNode := s.Find(REVIEW_RATE_CLASS_NAME)
-		rateMessage, _ := reviewRateNode.Attr("style")
-
-		rate := parseGooglePlayRate(rateMessage)
-
-		review := Review{
-			Author:    authorName,
-			Store:     "Google Play",
-			Title:     reviewTitle,
-			Message:   reviewMessage,
-			Rate:      rate,
-			UpdatedAt: date,
-			Permalink: fmt.Sprintf("%s%s", GOOGLE_PLAY_BASE_URI, reviewPermalink),
+		// sources without developer responses leave them empty, stored ones are kept
+		if review.DeveloperResponse != "" && review.DeveloperResponse != stored.DeveloperResponse {
+			_, err := dbh.Exec(`UPDATE review SET response = $1, response_at = $2 WHERE id = $3`,
+				review.DeveloperResponse, NullTime(review.DeveloperResponseAt), review.Id)
+			if err != nil {
+				return saved, err
+			}
+			saved.Responded = append(saved.Responded, review)
 		}
 
-		reviews = append(reviews, review)
-	})
-
-	sort.Sort(reviews)
-
-	return reviews, nil
-}
-
-func GetAppStoreReviews(config Config, uri string) (Reviews, error) {
-	log.Println(uri)
-
-	rssUri := fmt.Sprintf("%s/%s/rss/customerreviews/page=1/id=%s/sortBy=mostRecent/xml", APP_STORE_BASE_URI, config.AppStoreLocation, config.AppStoreAppId)
-	log.Println(rssUri)
-	response, err := http.Get(rssUri)
-	reviews := Reviews{}
-	if err != nil {
-		return nil, err
-	} else {
-		defer response.Body.Close()
-		contents, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			return nil, err
-		}
-		data, err := mxj.NewMapXml(contents)
-		if err != nil {
-			log.Fatal("parsing xml failed")
-			return nil, err
+		if stored.StoredHash == review.ContentHash() {
+			continue
 		}
 
-		entries, err := data.ValuesForPath("feed.entry")
+		// rows stored before native ids were kept get theirs on their first update
+		_, err = dbh.Exec(`UPDATE review SET rating = $1, title = $2, message = $3, content_hash = $4, updated_at = $5,
+			app_version = $6, language = $7, device = $8, os_version = $9, external_id = COALESCE(external_id, $10) WHERE id = $11`,
+			review.Rating, review.Title, review.Message, review.ContentHash(), review.UpdatedAt,
+			review.AppVersion, review.Language, review.Device, review.OSVersion, NullString(review.ExternalID), review.Id)
 		if err != nil {
-			log.Fatal("get xml entry failed")
-			return nil, err
+			return saved, err
 		}
-		for i, entry := range entries {
-			if i == 0 {
-				continue
-				// TODO: what's this
-			}
-			rate, _ := strconv.Atoi(entry.(map[string]interface{})["rating"].(string))
-			commonData := entry.(map[string]interface{})
-			author := commonData["author"].(map[string]interface{})
 
-			updatedAt, err := time.Parse(time.RFC3339, commonData["updated"].(string))
-			if err != nil {
-				log.Fatal("parse time failed")
-				return nil, err
-			}
-
-			message := commonData["content"].([]interface{})[0].(map[string]interface{})["#text"].(string)
-
-			review := Review{
-				Author:    author["name"].(string),
-				Store:     "App Store",
-				Title:     commonData["title"].(string),
-				Message:   message,
-				Rate:      parseAppStoreRate(rate),
-				UpdatedAt: updatedAt,
-				Permalink: author["uri"].(string),
-			}
-
-			reviews = append(reviews, review)
+		if err := dbh.SaveRevision(review); err != nil {
+			return saved, err
 		}
-	}
-	sort.Sort(reviews)
-	return reviews, nil
-}
-
-func parseAppStoreRate(count int) string {
-	rateMessage := ""
-	if count < 5 {
-		rateMessage = strings.Repeat(RATING_EMOJI, count)
-	} else {
-		rateMessage = strings.Repeat(RATING_EMOJI_2, count)
-	}
-
-	return rateMessage
-}
-
-func parseGooglePlayRate(message string) string {
-	rateMessage := ""
-
-	switch {
-	case strings.Contains(message, "width: 20%"):
-		rateMessage = strings.Repeat(RATING_EMOJI, 1)
-	case strings.Contains(message, "width: 40%"):
-		rateMessage = strings.Repeat(RATING_EMOJI, 2)
-	case strings.Contains(message, "width: 60%"):
-		rateMessage = strings.Repeat(RATING_EMOJI, 3)
-	case strings.Contains(message, "width: 80%"):
-		rateMessage = strings.Repeat(RATING_EMOJI, 4)
-	case strings.Contains(message, "width: 100%"):
-		rateMessage = strings.Repeat(RATING_EMOJI_2, 5)
-	}
-
-	return rateMessage
-}
 
-func SaveReviews(reviews Reviews) (Reviews, error) {
-	postReviews := Reviews{}
-
-	for _, review := range reviews {
-		var id int
-		row := dbh.QueryRow("SELECT id FROM review WHERE comment_uri = $1", review.Permalink)
-		err := row.Scan(&id)
-
-		if err != nil {
-			if err.Error() != "sql: no rows in result set" {
-				return postReviews, err
-			}
+		// content of reviews stored before hashes were kept is unknown, there is nothing to compare
+		if stored.StoredHash == "" {
+			continue
 		}
 
-		if id == 0 { // not exist
-			_, err := dbh.Exec("INSERT INTO review (author, store, comment_uri, updated_at) VALUES ($1, $2, $3, $4)",
-				review.Author, review.Store, review.Permalink, review.UpdatedAt)
-			if err != nil {
-				return postReviews, err
-			}
-			postReviews = append(postReviews, review)
+		if err := dbh.CountRatingChange(review.App, review.Store, stored.Rating, review.Rating); err != nil {
+			return saved, err
 		}
+		saved.Changed = append(saved.Changed, ReviewChange{Old: *stored, New: review})
 	}
 
-	return postReviews, nil
+	return saved, nil
 }
 
-func PostReview(config Config, reviews Reviews) error {
+func PostReview(config AppConfig, reviews Reviews) error {
 	attachments := []SlackAttachment{}
 
 	if 1 > len(reviews) {
@@ -540,11 +839,13 @@ func PostReview(config Config, reviews Reviews) error {
 
 		fields := []SlackAttachmentField{}
 
-		fields = append(fields, SlackAttachmentField{
-			Title: "Rating",
-			Value: review.Rate,
-			Short: true,
-		})
+		if review.Rate != "" {
+			fields = append(fields, SlackAttachmentField{
+				Title: "Rating",
+				Value: review.Rate,
+				Short: true,
+			})
+		}
 
 		fields = append(fields, SlackAttachmentField{
 			Title: "UpdatedAt",
@@ -552,19 +853,95 @@ func PostReview(config Config, reviews Reviews) error {
 			Short: true,
 		})
 
+		if review.Country != "" {
+			fields = append(fields, SlackAttachmentField{
+				Title: "Country",
+				Value: CountryLabel(review.Country),
+				Short: true,
+			})
+		}
+
+		if review.AppVersion != "" {
+			fields = append(fields, SlackAttachmentField{
+				Title: "Version",
+				Value: review.AppVersion,
+				Short: true,
+			})
+		}
+
+		if device := strings.TrimSpace(review.Device + " " + review.OSVersion); device != "" {
+			fields = append(fields, SlackAttachmentField{
+				Title: "Device",
+				Value: device,
+				Short: true,
+			})
+		}
+
+		if review.Language != "" {
+			fields = append(fields, SlackAttachmentField{
+				Title: "Language",
+				Value: review.Language,
+				Short: true,
+			})
+		}
+
+		if review.HelpfulVotes > 0 || review.UnhelpfulVotes > 0 {
+			fields = append(fields, SlackAttachmentField{
+				Title: "Helpful",
+				Value: fmt.Sprintf(":+1: %d :-1: %d", review.HelpfulVotes, review.UnhelpfulVotes),
+				Short: true,
+			})
+		}
+
+		if review.DeveloperResponse != "" {
+			fields = append(fields, SlackAttachmentField{
+				Title: "Developer Response",
+				Value: review.DeveloperResponse,
+			})
+		}
+
+		// slack shows a single image per attachment, further ones are linked.
+		// Screenshot links are signed and expire, the permalink keeps them.
+		imageURL := ""
+		if len(review.Screenshots) > 0 {
+			imageURL = review.Screenshots[0]
+
+			links := []string{}
+			for i, screenshot := range review.Screenshots[1:] {
+				links = append(links, fmt.Sprintf("<%s|Screenshot %d>", screenshot, i+2))
+			}
+			links = append(links, fmt.Sprintf("links expire, <%s|open the feedback> later on", review.Permalink))
+			fields = append(fields, SlackAttachmentField{
+				Title: "Screenshots",
+				Value: strings.Join(links, " · "),
+			})
+		}
+
 		attachments = append(attachments, SlackAttachment{
 			Title:      review.Title,
 			TitleLink:  review.Permalink,
 			AuthorName: review.Author,
+			AuthorLink: review.AuthorLink,
 			Text:       review.Message,
 			Fallback:   review.Message + " " + review.Author,
 			Color:      review.Color,
 			Fields:     fields,
 			Footer:     review.Store,
+			ImageURL:   imageURL,
 		})
 	}
 
 	messageText := reviews[0].Store + " Reviews:"
+	if config.Name != "" {
+		messageText = config.Name + " " + messageText
+	}
+	trend, err := StoreRatingTrend(config.Key(), reviews[0].Store, RATING_TREND_PERIOD)
+	if err != nil {
+		log.Println(err)
+	}
+	if trend != "" {
+		messageText += " (" + trend + ")"
+	}
 	slackPayload := SlackPayload{
 		UserName:    config.BotName,
 		IconEmoji:   config.IconEmoji,
@@ -572,12 +949,17 @@ func PostReview(config Config, reviews Reviews) error {
 		Attachments: attachments,
 	}
 
+	return SendSlackPayload(config.WebHookUri, slackPayload)
+}
+
+// SendSlackPayload posts payload to a slack incoming webhook.
+func SendSlackPayload(webHookUri string, slackPayload SlackPayload) error {
 	payload, err := json.Marshal(slackPayload)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", config.WebHookUri, bytes.NewBuffer([]byte(payload)))
+	req, err := http.NewRequest("POST", webHookUri, bytes.NewBuffer([]byte(payload)))
 	req.Header.Set("Content-Type", "application/json")
 
 	if err != nil {
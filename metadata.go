@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	METADATA_TABLE_NAME = "metadata_snapshot"
+	ITUNES_LOOKUP_URI   = APP_STORE_BASE_URI + "/lookup"
+)
+
+var (
+	metadataClient = &http.Client{Timeout: 30 * time.Second}
+	// storeListings keeps listing pages fetched during the run by uri.
+	storeListings   = map[string][]byte{}
+	storeListingsMu sync.Mutex
+)
+
+// AppMetadata is the store listing of an app at a point in time.
+type AppMetadata struct {
+	Store            string
+	Country          string
+	Version          string
+	ReleaseNotes     string
+	Price            string
+	Size             int64
+	MinimumOSVersion string
+	AverageRating    float64
+	RatingCount      int
+}
+
+// MetadataChange is a listing field which changed between two snapshots.
+type MetadataChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// StoreListing returns the listing page at uri, fetched once per run.
+func StoreListing(uri string) ([]byte, error) {
+	storeListingsMu.Lock()
+	defer storeListingsMu.Unlock()
+
+	if body, ok := storeListings[uri]; ok {
+		return body, nil
+	}
+
+	res, err := metadataClient.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("URI: %s is not exists", uri)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	storeListings[uri] = body
+	return body, nil
+}
+
+// GooglePlayListingURI returns the Play Store listing page of an app in the
+// hl language and gl country, empty ones are left to the store.
+func GooglePlayListingURI(id string, hl string, gl string) string {
+	query := url.Values{}
+	query.Add("id", id)
+	if hl != "" {
+		query.Add("hl", hl)
+	}
+	if gl != "" {
+		query.Add("gl", gl)
+	}
+	return GOOGLE_PLAY_BASE_URI + "/store/apps/details?" + query.Encode()
+}
+
+type itunesLookup struct {
+	Results []struct {
+		Version           string  `json:"version"`
+		ReleaseNotes      string  `json:"releaseNotes"`
+		FormattedPrice    string  `json:"formattedPrice"`
+		FileSizeBytes     string  `json:"fileSizeBytes"`
+		MinimumOsVersion  string  `json:"minimumOsVersion"`
+		AverageUserRating float64 `json:"averageUserRating"`
+		UserRatingCount   int     `json:"userRatingCount"`
+	} `json:"results"`
+}
+
+// GetAppStoreMetadata reads the listing of the app in a storefront through
+// the iTunes lookup API.
+func GetAppStoreMetadata(config AppConfig, country string) (AppMetadata, error) {
+	query := url.Values{}
+	query.Add("id", config.AppStoreAppId)
+	query.Add("country", country)
+	uri := ITUNES_LOOKUP_URI + "?" + query.Encode()
+	log.Println(uri)
+
+	res, err := metadataClient.Get(uri)
+	if err != nil {
+		return AppMetadata{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return AppMetadata{}, fmt.Errorf("%s responded %s", uri, res.Status)
+	}
+
+	var lookup itunesLookup
+	if err := json.NewDecoder(res.Body).Decode(&lookup); err != nil {
+		return AppMetadata{}, fmt.Errorf("decoding iTunes lookup failed: %v", err)
+	}
+	if len(lookup.Results) == 0 {
+		return AppMetadata{}, fmt.Errorf("App Store app %s not found in %s", config.AppStoreAppId, country)
+	}
+
+	result := lookup.Results[0]
+	size, _ := strconv.ParseInt(result.FileSizeBytes, 10, 64)
+	return AppMetadata{
+		Store:            APP_STORE_NAME,
+		Country:          country,
+		Version:          result.Version,
+		ReleaseNotes:     strings.TrimSpace(result.ReleaseNotes),
+		Price:            result.FormattedPrice,
+		Size:             size,
+		MinimumOSVersion: result.MinimumOsVersion,
+		AverageRating:    result.AverageUserRating,
+		RatingCount:      result.UserRatingCount,
+	}, nil
+}
+
+type googlePlayListing struct {
+	AggregateRating struct {
+		RatingValue json.Number `json:"ratingValue"`
+		RatingCount json.Number `json:"ratingCount"`
+	} `json:"aggregateRating"`
+	Offers []struct {
+		Price         json.Number `json:"price"`
+		PriceCurrency string      `json:"priceCurrency"`
+	} `json:"offers"`
+}
+
+// GetGooglePlayMetadata reads the listing of the app in a language and
+// country from the structured data of its Play Store page. The page does not
+// expose version, size or minimum OS version, they are left empty.
+func GetGooglePlayMetadata(config AppConfig, locale GooglePlayLocale) (AppMetadata, error) {
+	uri := GooglePlayListingURI(config.GooglePlayAppId, locale.Language, locale.Country)
+
+	body, err := StoreListing(uri)
+	if err != nil {
+		return AppMetadata{}, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return AppMetadata{}, err
+	}
+
+	var listing googlePlayListing
+	found := false
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		found = json.Unmarshal([]byte(s.Text()), &listing) == nil && listing.AggregateRating.RatingValue != ""
+		return !found
+	})
+	if !found {
+		return AppMetadata{}, fmt.Errorf("no listing data found in %s", uri)
+	}
+
+	metadata := AppMetadata{Store: GOOGLE_PLAY_NAME, Country: locale.Country}
+	metadata.AverageRating, _ = listing.AggregateRating.RatingValue.Float64()
+	count, _ := listing.AggregateRating.RatingCount.Int64()
+	metadata.RatingCount = int(count)
+	if len(listing.Offers) > 0 {
+		metadata.Price = strings.TrimSpace(string(listing.Offers[0].Price) + " " + listing.Offers[0].PriceCurrency)
+	}
+	return metadata, nil
+}
+
+// LastMetadata returns the latest snapshot of the listing of app on a store,
+// nil before the first one.
+func (dbh *DBH) LastMetadata(app string, store string, country string) (*AppMetadata, error) {
+	row := dbh.QueryRow(`SELECT version, release_notes, price, size, minimum_os_version, average_rating, rating_count
+		FROM `+METADATA_TABLE_NAME+` WHERE app = $1 AND store = $2 AND country = $3 ORDER BY id DESC LIMIT 1`, app, store, country)
+
+	metadata := AppMetadata{Store: store, Country: country}
+	err := row.Scan(&metadata.Version, &metadata.ReleaseNotes, &metadata.Price, &metadata.Size,
+		&metadata.MinimumOSVersion, &metadata.AverageRating, &metadata.RatingCount)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// SaveMetadata stores a snapshot of the listing of app.
+func (dbh *DBH) SaveMetadata(app string, metadata AppMetadata) error {
+	_, err := dbh.Exec(`INSERT INTO `+METADATA_TABLE_NAME+` (app, store, country, version, release_notes, price, size,
+		minimum_os_version, average_rating, rating_count) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		app, metadata.Store, metadata.Country, metadata.Version, metadata.ReleaseNotes, metadata.Price, metadata.Size,
+		metadata.MinimumOSVersion, metadata.AverageRating, metadata.RatingCount)
+	return err
+}
+
+// Diff lists the fields changed from old to metadata. A rating count moving
+// alone is not reported, it changes on almost every run of popular apps.
+func (metadata AppMetadata) Diff(old AppMetadata) []MetadataChange {
+	changes := []MetadataChange{}
+	add := func(field string, from string, to string) {
+		if from != to {
+			changes = append(changes, MetadataChange{field, from, to})
+		}
+	}
+
+	add("Version", old.Version, metadata.Version)
+	add("Price", old.Price, metadata.Price)
+	add("Size", formatSize(old.Size), formatSize(metadata.Size))
+	add("Minimum OS", old.MinimumOSVersion, metadata.MinimumOSVersion)
+	add("Average Rating", formatAverage(old.AverageRating), formatAverage(metadata.AverageRating))
+	add("Release Notes", old.ReleaseNotes, metadata.ReleaseNotes)
+
+	if len(changes) > 0 && old.RatingCount != metadata.RatingCount {
+		changes = append(changes, MetadataChange{"Rating Count", strconv.Itoa(old.RatingCount), strconv.Itoa(metadata.RatingCount)})
+	}
+	return changes
+}
+
+func formatSize(size int64) string {
+	if size == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+}
+
+// formatAverage rounds average ratings to two decimals, finer moves are noise.
+func formatAverage(average float64) string {
+	if average == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(math.Round(average*100)/100, 'f', 2, 64)
+}
+
+// TrackMetadata snapshots the store listings of app and posts the changes
+// since the previous snapshot. Listings are posted to the digest webhook of
+// competitor apps. A listing failing to load is logged, the other one is
+// still tracked.
+func TrackMetadata(app AppConfig) error {
+	listings := []AppMetadata{}
+
+	if app.AppStoreAppId != "" {
+		metadata, err := GetAppStoreMetadata(app, app.PrimaryStorefront())
+		if err != nil {
+			log.Printf("App Store listing of %s: %v", app.Key(), err)
+		} else {
+			listings = append(listings, metadata)
+		}
+	}
+
+	if app.GooglePlayAppId != "" {
+		metadata, err := GetGooglePlayMetadata(app, GooglePlayLocale{Language: app.GooglePlayLocation})
+		if err != nil {
+			log.Printf("Google Play listing of %s: %v", app.Key(), err)
+		} else {
+			listings = append(listings, metadata)
+		}
+	}
+
+	for _, metadata := range listings {
+		old, err := dbh.LastMetadata(app.Key(), metadata.Store, metadata.Country)
+		if err != nil {
+			return err
+		}
+
+		changes := []MetadataChange{}
+		if old != nil {
+			changes = metadata.Diff(*old)
+			if len(changes) == 0 && old.RatingCount == metadata.RatingCount {
+				continue
+			}
+		}
+
+		if err := dbh.SaveMetadata(app.Key(), metadata); err != nil {
+			return err
+		}
+
+		if err := PostMetadataChanges(app, metadata, changes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PostMetadataChanges posts the changed fields of a store listing.
+func PostMetadataChanges(config AppConfig, metadata AppMetadata, changes []MetadataChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	fields := []SlackAttachmentField{}
+	for _, change := range changes {
+		if change.Field == "Release Notes" {
+			fields = append(fields, SlackAttachmentField{
+				Title: change.Field,
+				Value: WordDiff(change.Old, change.New),
+			})
+			continue
+		}
+
+		fields = append(fields, SlackAttachmentField{
+			Title: change.Field,
+			Value: fmt.Sprintf("%s → %s", orNone(change.Old), orNone(change.New)),
+			Short: true,
+		})
+	}
+
+	footer := metadata.Store
+	if metadata.Country != "" {
+		footer += " " + CountryLabel(metadata.Country)
+	}
+
+	webHookUri := config.WebHookUri
+	if config.Competitor {
+		webHookUri = config.DigestWebHookUri
+	}
+
+	return SendSlackPayload(webHookUri, SlackPayload{
+		UserName:  config.BotName,
+		IconEmoji: config.IconEmoji,
+		Text:      config.Key() + " " + metadata.Store + " Listing Updated:",
+		Attachments: []SlackAttachment{
+			{
+				Fallback: fmt.Sprintf("%s listing of %s changed", metadata.Store, config.Key()),
+				Fields:   fields,
+				Footer:   footer,
+			},
+		},
+	})
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
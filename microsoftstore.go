@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MICROSOFT_STORE_NAME           = "Microsoft Store"
+	MICROSOFT_STORE_API_URI        = "https://storeedgefd.dsx.mp.microsoft.com/v9.0/ratings/product/"
+	MICROSOFT_STORE_APP_URI        = "https://apps.microsoft.com/detail/"
+	MICROSOFT_STORE_DEFAULT_MARKET = "US"
+	MICROSOFT_STORE_LOCALE         = "en-US"
+	MICROSOFT_STORE_PAGE_SIZE      = 25
+	MICROSOFT_STORE_MAX_PAGES      = 10
+)
+
+var microsoftStoreClient = &http.Client{Timeout: 30 * time.Second}
+
+func init() {
+	RegisterSource(MICROSOFT_STORE_NAME, NewMicrosoftStoreSource)
+}
+
+type MicrosoftStoreSource struct {
+	config AppConfig
+}
+
+func NewMicrosoftStoreSource(config AppConfig) ReviewSource {
+	if config.MicrosoftStoreProductId == "" {
+		return nil
+	}
+	return &MicrosoftStoreSource{config}
+}
+
+func (s *MicrosoftStoreSource) Name() string {
+	return MICROSOFT_STORE_NAME
+}
+
+func (s *MicrosoftStoreSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true, Countries: true}
+}
+
+type microsoftStoreRatings struct {
+	AverageRating float64 `json:"AverageRating"`
+	RatingCount   int     `json:"RatingCount"`
+	Star1Count    int     `json:"Star1Count"`
+	Star2Count    int     `json:"Star2Count"`
+	Star3Count    int     `json:"Star3Count"`
+	Star4Count    int     `json:"Star4Count"`
+	Star5Count    int     `json:"Star5Count"`
+}
+
+// RatingSummaries reads the rating and star histogram of the product in the
+// configured market.
+func (s *MicrosoftStoreSource) RatingSummaries() ([]RatingSummary, error) {
+	market := strings.ToUpper(s.config.MicrosoftStoreMarket)
+
+	query := url.Values{}
+	query.Add("market", market)
+	query.Add("locale", MICROSOFT_STORE_LOCALE)
+	uri := MICROSOFT_STORE_API_URI + url.PathEscape(s.config.MicrosoftStoreProductId) + "?" + query.Encode()
+	log.Println(uri)
+
+	res, err := microsoftStoreClient.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded %s", uri, res.Status)
+	}
+
+	var ratings microsoftStoreRatings
+	if err := json.NewDecoder(res.Body).Decode(&ratings); err != nil {
+		return nil, fmt.Errorf("decoding Microsoft Store ratings failed: %v", err)
+	}
+
+	return []RatingSummary{{
+		Store:     MICROSOFT_STORE_NAME,
+		Country:   strings.ToLower(market),
+		Average:   ratings.AverageRating,
+		Count:     ratings.RatingCount,
+		Histogram: []int{ratings.Star1Count, ratings.Star2Count, ratings.Star3Count, ratings.Star4Count, ratings.Star5Count},
+	}}, nil
+}
+
+type microsoftStoreReviews struct {
+	Payload struct {
+		Reviews []microsoftStoreReview `json:"Reviews"`
+	} `json:"Payload"`
+}
+
+type microsoftStoreReview struct {
+	ReviewId             string    `json:"ReviewId"`
+	ReviewerName         string    `json:"ReviewerName"`
+	Rating               float64   `json:"Rating"`
+	Title                string    `json:"Title"`
+	ReviewText           string    `json:"ReviewText"`
+	SubmittedDateTimeUtc time.Time `json:"SubmittedDateTimeUtc"`
+	HelpfulPositive      int       `json:"HelpfulPositive"`
+	HelpfulNegative      int       `json:"HelpfulNegative"`
+	Market               string    `json:"Market"`
+	Locale               string    `json:"Locale"`
+	ProductVersion       string    `json:"ProductVersion"`
+	DeviceFamily         string    `json:"DeviceFamily"`
+}
+
+// Fetch walks the most recent first review pages until a known review or
+// one older than since is reached.
+func (s *MicrosoftStoreSource) Fetch(since time.Time) (Reviews, error) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for page := 1; page <= MaxPages(MICROSOFT_STORE_MAX_PAGES); page++ {
+		pageReviews, pageErrors, hasNext, err := GetMicrosoftStoreReviews(s.config, page)
+		if err != nil {
+			return nil, err
+		}
+		parseErrors = append(parseErrors, pageErrors...)
+
+		unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, unseen...)
+		if done || !hasNext {
+			break
+		}
+	}
+
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
+
+// GetMicrosoftStoreReviews returns a page of reviews of the product in the
+// configured market from the public ratings endpoint, and whether a next
+// page may exist.
+func GetMicrosoftStoreReviews(config AppConfig, page int) (Reviews, ParseErrors, bool, error) {
+	market := strings.ToUpper(config.MicrosoftStoreMarket)
+
+	query := url.Values{}
+	query.Add("market", market)
+	query.Add("locale", MICROSOFT_STORE_LOCALE)
+	query.Add("sortBy", "MostRecent")
+	query.Add("skipItems", strconv.Itoa((page-1)*MICROSOFT_STORE_PAGE_SIZE))
+	query.Add("pageSize", strconv.Itoa(MICROSOFT_STORE_PAGE_SIZE))
+	uri := MICROSOFT_STORE_API_URI + url.PathEscape(config.MicrosoftStoreProductId) + "/reviews?" + query.Encode()
+	log.Println(uri)
+
+	res, err := microsoftStoreClient.Get(uri)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, false, fmt.Errorf("%s responded %s", uri, res.Status)
+	}
+
+	var list microsoftStoreReviews
+	if err := json.NewDecoder(res.Body).Decode(&list); err != nil {
+		return nil, nil, false, fmt.Errorf("decoding Microsoft Store reviews failed: %v", err)
+	}
+
+	reviews, parseErrors := ParseMicrosoftStoreReviews(config.MicrosoftStoreProductId, market, list)
+	for _, parseError := range parseErrors {
+		parseError.Page = page
+	}
+	hasNext := len(list.Payload.Reviews) == MICROSOFT_STORE_PAGE_SIZE
+	return reviews, parseErrors, hasNext, nil
+}
+
+// ParseMicrosoftStoreReviews maps a page of reviews of productId in market.
+func ParseMicrosoftStoreReviews(productId string, market string, list microsoftStoreReviews) (Reviews, ParseErrors) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for i, entry := range list.Payload.Reviews {
+		review, err := parseMicrosoftStoreReview(productId, market, entry)
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{
+				Source:  MICROSOFT_STORE_NAME,
+				Country: strings.ToLower(market),
+				Entry:   i,
+				Err:     err,
+			})
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	sort.Sort(reviews)
+	return reviews, parseErrors
+}
+
+func parseMicrosoftStoreReview(productId string, market string, entry microsoftStoreReview) (Review, error) {
+	if entry.ReviewId == "" {
+		return Review{}, fmt.Errorf("missing review id")
+	}
+
+	// ratings are averaged over revisions on some products, round to stars
+	rate := int(entry.Rating + 0.5)
+	if rate < 1 || rate > 5 {
+		return Review{}, fmt.Errorf("invalid rating %v of review %s", entry.Rating, entry.ReviewId)
+	}
+
+	if entry.SubmittedDateTimeUtc.IsZero() {
+		return Review{}, fmt.Errorf("missing date of review %s", entry.ReviewId)
+	}
+
+	if entry.Market != "" {
+		market = entry.Market
+	}
+
+	title := strings.TrimSpace(entry.Title)
+	if len(title) == 0 {
+		title = "No title provided"
+	}
+
+	return Review{
+		ExternalID:     entry.ReviewId,
+		Author:         entry.ReviewerName,
+		Store:          MICROSOFT_STORE_NAME,
+		Country:        strings.ToLower(market),
+		Title:          title,
+		Message:        strings.TrimSpace(entry.ReviewText),
+		Rating:         rate,
+		Rate:           parseAppStoreRate(rate),
+		UpdatedAt:      entry.SubmittedDateTimeUtc,
+		Permalink:      MICROSOFT_STORE_APP_URI + url.PathEscape(productId) + "?gl=" + url.QueryEscape(market),
+		AppVersion:     entry.ProductVersion,
+		Language:       entry.Locale,
+		Device:         entry.DeviceFamily,
+		HelpfulVotes:   entry.HelpfulPositive,
+		UnhelpfulVotes: entry.HelpfulNegative,
+	}, nil
+}
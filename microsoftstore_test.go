@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseMicrosoftStoreReviews(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/microsoftstore_reviews.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var list microsoftStoreReviews
+	if err := json.Unmarshal(fixture, &list); err != nil {
+		t.Fatal(err)
+	}
+
+	reviews, parseErrors := ParseMicrosoftStoreReviews("9NBLGGH4NNS1", MICROSOFT_STORE_DEFAULT_MARKET, list)
+	if len(parseErrors) != 1 || parseErrors[0].Entry != 2 || parseErrors[0].Country != "us" {
+		t.Errorf("skipped entries are %v, want entry 2 of us", parseErrors)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("%d reviews, want 2", len(reviews))
+	}
+
+	// reviews keep the market they were written in
+	review := reviews[0]
+	if review.ExternalID != "5d1c0e7b-6c2f-4a55-9e0a-2b7d3f4a1c9e" || review.Author != "Jane Doe" ||
+		review.Store != MICROSOFT_STORE_NAME || review.Country != "gb" || review.Title != "Solid app" ||
+		review.Message != "Works great with the pen, wish it had tabs." || review.Rating != 4 || review.Rate != parseAppStoreRate(4) {
+		t.Errorf("first review is %+v", review)
+	}
+	if review.AppVersion != "4.1.0.0" || review.Language != "en-GB" || review.Device != "Windows.Desktop" ||
+		review.HelpfulVotes != 7 || review.UnhelpfulVotes != 1 {
+		t.Errorf("first review metadata is %+v", review)
+	}
+	if !review.UpdatedAt.Equal(time.Date(2023, 1, 2, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("first review is dated %v", review.UpdatedAt)
+	}
+	if review.Permalink != "https://apps.microsoft.com/detail/9NBLGGH4NNS1?gl=GB" {
+		t.Errorf("first review permalink is %s", review.Permalink)
+	}
+
+	// averaged ratings are rounded, reviews without a market keep the requested one
+	review = reviews[1]
+	if review.ExternalID != "0a9b8c7d-6e5f-4a3b-8c2d-1e0f9a8b7c6d" || review.Rating != 2 || review.Country != "us" ||
+		review.Title != "No title provided" || review.Permalink != "https://apps.microsoft.com/detail/9NBLGGH4NNS1?gl=US" {
+		t.Errorf("second review is %+v", review)
+	}
+}
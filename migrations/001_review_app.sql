@@ -0,0 +1,4 @@
+-- Tag reviews with the app they belong to.
+-- Existing rows keep an empty app and still count as seen for every app.
+ALTER TABLE review ADD COLUMN app VARCHAR(255) NOT NULL DEFAULT '';
+CREATE INDEX app_idx on review(app);
@@ -0,0 +1,2 @@
+-- Tag reviews with the storefront country they were written in.
+ALTER TABLE review ADD COLUMN country VARCHAR(8) NOT NULL DEFAULT '';
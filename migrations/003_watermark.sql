@@ -0,0 +1,8 @@
+-- Newest review date seen per app, store and storefront country.
+CREATE TABLE watermark (
+  app VARCHAR(255) NOT NULL,
+  store VARCHAR(255) NOT NULL,
+  country VARCHAR(8) NOT NULL DEFAULT '',
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (app, store, country)
+);
@@ -0,0 +1,22 @@
+-- Deduplicate reviews on the store native review id.
+ALTER TABLE review ADD COLUMN external_id VARCHAR(255) NULL;
+
+-- Backfill ids found in permalinks: Google Play permalinks carry reviewId,
+-- App Store Connect permalinks end with the review id. App Store RSS rows
+-- only kept the reviewer profile URL and cannot be backfilled.
+UPDATE review SET external_id = backfill.external_id
+FROM (
+  SELECT DISTINCT ON (store, app, external_id) id, external_id
+  FROM (
+    SELECT id, store, app, substring(comment_uri from 'reviewId=([^&]+)') AS external_id
+    FROM review WHERE store = 'Google Play'
+    UNION ALL
+    SELECT id, store, app, substring(comment_uri from '^https://apps\.apple\.com/.*#(.+)$') AS external_id
+    FROM review WHERE store = 'App Store'
+  ) candidates
+  WHERE external_id IS NOT NULL
+  ORDER BY store, app, external_id, id
+) backfill
+WHERE review.id = backfill.id;
+
+CREATE UNIQUE INDEX external_id_idx on review(store, app, external_id);
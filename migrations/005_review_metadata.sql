@@ -0,0 +1,5 @@
+-- Version, language and device the review was written from, when the store tells.
+ALTER TABLE review ADD COLUMN app_version VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE review ADD COLUMN language VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE review ADD COLUMN device VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE review ADD COLUMN os_version VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,27 @@
+-- Keep review content to detect edits, with the history of every revision.
+-- Rows stored before keep an empty hash, their next fetch only records content.
+ALTER TABLE review ADD COLUMN rating INT NOT NULL DEFAULT 0;
+ALTER TABLE review ADD COLUMN title TEXT NOT NULL DEFAULT '';
+ALTER TABLE review ADD COLUMN message TEXT NOT NULL DEFAULT '';
+ALTER TABLE review ADD COLUMN content_hash VARCHAR(40) NOT NULL DEFAULT '';
+
+CREATE TABLE review_revision (
+  id SERIAL PRIMARY KEY,
+  review_id INT NOT NULL REFERENCES review(id) ON DELETE CASCADE,
+  rating INT NOT NULL,
+  title TEXT NOT NULL,
+  message TEXT NOT NULL,
+  content_hash VARCHAR(40) NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX review_revision_review_id_idx on review_revision(review_id);
+
+-- Edits raising or lowering the rating, per app and store.
+CREATE TABLE rating_change (
+  app VARCHAR(255) NOT NULL,
+  store VARCHAR(255) NOT NULL,
+  upgrades INT NOT NULL DEFAULT 0,
+  downgrades INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (app, store)
+);
@@ -0,0 +1,3 @@
+-- Developer response to the review and when it was last edited.
+ALTER TABLE review ADD COLUMN response TEXT NOT NULL DEFAULT '';
+ALTER TABLE review ADD COLUMN response_at TIMESTAMP WITH TIME ZONE NULL;
@@ -0,0 +1,5 @@
+-- Whether the review was posted to slack, reviews over the review count of a
+-- run and backfilled ones are only stored. Reviews stored so far are
+-- considered notified.
+ALTER TABLE review ADD COLUMN notified BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE review SET notified = TRUE;
@@ -0,0 +1,6 @@
+-- Newest review covered by the last digest of a competitor app.
+CREATE TABLE digest (
+  app VARCHAR(255) PRIMARY KEY,
+  review_id INT NOT NULL,
+  sent_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
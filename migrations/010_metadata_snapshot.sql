@@ -0,0 +1,16 @@
+-- Store listing snapshots, a row is added whenever the listing changes.
+CREATE TABLE metadata_snapshot (
+  id SERIAL PRIMARY KEY,
+  app VARCHAR(255) NOT NULL,
+  store VARCHAR(255) NOT NULL,
+  country VARCHAR(8) NOT NULL DEFAULT '',
+  version VARCHAR(255) NOT NULL DEFAULT '',
+  release_notes TEXT NOT NULL DEFAULT '',
+  price VARCHAR(255) NOT NULL DEFAULT '',
+  size BIGINT NOT NULL DEFAULT 0,
+  minimum_os_version VARCHAR(255) NOT NULL DEFAULT '',
+  average_rating DOUBLE PRECISION NOT NULL DEFAULT 0,
+  rating_count INT NOT NULL DEFAULT 0,
+  recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX metadata_snapshot_app_idx on metadata_snapshot(app, store, country);
@@ -0,0 +1,17 @@
+-- Overall rating and rating count shown by stores, recorded on every run.
+-- Star counts are NULL for stores hiding the histogram.
+CREATE TABLE rating_snapshot (
+  id SERIAL PRIMARY KEY,
+  app VARCHAR(255) NOT NULL,
+  store VARCHAR(255) NOT NULL,
+  country VARCHAR(8) NOT NULL DEFAULT '',
+  average_rating DOUBLE PRECISION NOT NULL,
+  rating_count INT NOT NULL,
+  stars_1 INT NULL,
+  stars_2 INT NULL,
+  stars_3 INT NULL,
+  stars_4 INT NULL,
+  stars_5 INT NULL,
+  recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX rating_snapshot_app_idx on rating_snapshot(app, store, country, recorded_at);
@@ -0,0 +1,2 @@
+-- Google Play watermarks are kept per language and country, such as fr_CA.
+ALTER TABLE watermark ALTER COLUMN country TYPE VARCHAR(32);
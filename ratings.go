@@ -0,0 +1,290 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	RATING_SNAPSHOT_TABLE_NAME = "rating_snapshot"
+	// RATING_SNAPSHOT_INTERVAL is how often overall ratings are recorded,
+	// runs in between skip them.
+	RATING_SNAPSHOT_INTERVAL = 24 * time.Hour
+	// RATING_TREND_PERIOD is how far back trends look by default.
+	RATING_TREND_PERIOD = 7 * 24 * time.Hour
+)
+
+// RatingSummary is the overall rating of an app on a store as shown by the
+// store, in a country when the store rates per country.
+type RatingSummary struct {
+	Store   string
+	Country string
+	Average float64
+	Count   int
+	// Histogram counts ratings of 1 to 5 stars, nil when the store hides it.
+	Histogram []int
+	// RecordedAt is when a stored summary was taken.
+	RecordedAt time.Time
+}
+
+// RatingSource is implemented by review sources able to read the overall
+// rating of the app.
+type RatingSource interface {
+	RatingSummaries() ([]RatingSummary, error)
+}
+
+// RecordRatings stores the current overall ratings of source once every
+// RATING_SNAPSHOT_INTERVAL, sources without overall ratings are skipped.
+func RecordRatings(app AppConfig, source ReviewSource) error {
+	ratingSource, ok := source.(RatingSource)
+	if !ok {
+		return nil
+	}
+
+	recordedAt, err := dbh.LastRatingAt(app.Key(), source.Name())
+	if err != nil {
+		return err
+	}
+	if time.Since(recordedAt) < RATING_SNAPSHOT_INTERVAL {
+		return nil
+	}
+
+	summaries, err := ratingSource.RatingSummaries()
+	if err != nil {
+		return err
+	}
+
+	for _, summary := range summaries {
+		if err := dbh.SaveRatingSummary(app.Key(), summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveRatingSummary appends a snapshot of the overall rating of app.
+func (dbh *DBH) SaveRatingSummary(app string, summary RatingSummary) error {
+	stars := make([]sql.NullInt64, 5)
+	if len(summary.Histogram) == 5 {
+		for i, count := range summary.Histogram {
+			stars[i] = sql.NullInt64{Int64: int64(count), Valid: true}
+		}
+	}
+
+	_, err := dbh.Exec(`INSERT INTO `+RATING_SNAPSHOT_TABLE_NAME+` (app, store, country, average_rating, rating_count,
+		stars_1, stars_2, stars_3, stars_4, stars_5) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		app, summary.Store, summary.Country, summary.Average, summary.Count,
+		stars[0], stars[1], stars[2], stars[3], stars[4])
+	return err
+}
+
+// LastRatingAt returns when ratings of app on store were last recorded, the
+// zero time before the first snapshot.
+func (dbh *DBH) LastRatingAt(app string, store string) (time.Time, error) {
+	var recordedAt pq.NullTime
+	err := dbh.QueryRow(`SELECT MAX(recorded_at) FROM `+RATING_SNAPSHOT_TABLE_NAME+` WHERE app = $1 AND store = $2`,
+		app, store).Scan(&recordedAt)
+	return recordedAt.Time, err
+}
+
+const ratingSnapshotColumns = `store, country, average_rating, rating_count, stars_1, stars_2, stars_3, stars_4, stars_5, recorded_at`
+
+func scanRatingSummary(scan func(dest ...interface{}) error) (RatingSummary, error) {
+	summary := RatingSummary{}
+	stars := make([]sql.NullInt64, 5)
+	err := scan(&summary.Store, &summary.Country, &summary.Average, &summary.Count,
+		&stars[0], &stars[1], &stars[2], &stars[3], &stars[4], &summary.RecordedAt)
+	if err != nil {
+		return summary, err
+	}
+
+	if stars[0].Valid {
+		summary.Histogram = make([]int, 5)
+		for i, count := range stars {
+			summary.Histogram[i] = int(count.Int64)
+		}
+	}
+	return summary, nil
+}
+
+// LatestRatings returns the latest snapshot of every store and country of app.
+func (dbh *DBH) LatestRatings(app string) ([]RatingSummary, error) {
+	rows, err := dbh.Query(`SELECT DISTINCT ON (store, country) `+ratingSnapshotColumns+` FROM `+RATING_SNAPSHOT_TABLE_NAME+`
+		WHERE app = $1 ORDER BY store, country, recorded_at DESC`, app)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []RatingSummary{}
+	for rows.Next() {
+		summary, err := scanRatingSummary(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// RatingAt returns the snapshot of app on a store and country taken last
+// before t, or the first one taken after when history is shorter. It
+// returns nil without any snapshot.
+func (dbh *DBH) RatingAt(app string, store string, country string, t time.Time) (*RatingSummary, error) {
+	row := dbh.QueryRow(`SELECT `+ratingSnapshotColumns+` FROM `+RATING_SNAPSHOT_TABLE_NAME+`
+		WHERE app = $1 AND store = $2 AND country = $3
+		ORDER BY recorded_at <= $4 DESC, ABS(EXTRACT(EPOCH FROM recorded_at - $4)) LIMIT 1`,
+		app, store, country, t)
+
+	summary, err := scanRatingSummary(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// RatingTrend describes how the rating of app moved since from, such as
+// "average fell from 4.6 to 4.4 since last week, +120 ratings".
+func RatingTrend(from RatingSummary, to RatingSummary) string {
+	fromAverage, toAverage := roundAverage(from.Average), roundAverage(to.Average)
+
+	trend := "average held at " + formatRating(toAverage)
+	switch {
+	case toAverage > fromAverage:
+		trend = fmt.Sprintf("average rose from %s to %s", formatRating(fromAverage), formatRating(toAverage))
+	case toAverage < fromAverage:
+		trend = fmt.Sprintf("average fell from %s to %s", formatRating(fromAverage), formatRating(toAverage))
+	}
+	trend += " since " + sinceLabel(to.RecordedAt.Sub(from.RecordedAt))
+
+	if delta := to.Count - from.Count; delta != 0 {
+		trend += fmt.Sprintf(", %+d ratings", delta)
+	}
+	return trend
+}
+
+// ratingTrendOver returns the trend of current over period, "" until the
+// snapshots span the period. Snapshots are taken on runs rather than at
+// fixed times, half an interval short of the period is close enough.
+func ratingTrendOver(app string, current RatingSummary, period time.Duration) (string, error) {
+	past, err := dbh.RatingAt(app, current.Store, current.Country, current.RecordedAt.Add(-period))
+	if err != nil || past == nil {
+		return "", err
+	}
+	if current.RecordedAt.Sub(past.RecordedAt) < period-RATING_SNAPSHOT_INTERVAL/2 {
+		return "", nil
+	}
+	return RatingTrend(*past, current), nil
+}
+
+// StoreRatingTrend returns the trend of the most rated country of app on a
+// store over period, "" without enough history.
+func StoreRatingTrend(app string, store string, period time.Duration) (string, error) {
+	latest, err := dbh.LatestRatings(app)
+	if err != nil {
+		return "", err
+	}
+
+	var current *RatingSummary
+	for i, summary := range latest {
+		if summary.Store == store && (current == nil || summary.Count > current.Count) {
+			current = &latest[i]
+		}
+	}
+	if current == nil {
+		return "", nil
+	}
+
+	return ratingTrendOver(app, *current, period)
+}
+
+func roundAverage(average float64) float64 {
+	return math.Round(average*10) / 10
+}
+
+func formatRating(average float64) string {
+	return strconv.FormatFloat(average, 'f', 1, 64)
+}
+
+// sinceLabel names a period as people would, "last week" for seven days.
+func sinceLabel(period time.Duration) string {
+	days := int(math.Round(period.Hours() / 24))
+	switch {
+	case days == 1:
+		return "yesterday"
+	case days == 7:
+		return "last week"
+	case days >= 28 && days <= 31:
+		return "last month"
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
+
+// histogramLabel renders star counts as "5★ 120 · 4★ 30 · ...".
+func histogramLabel(histogram []int) string {
+	if len(histogram) != 5 {
+		return ""
+	}
+	parts := []string{}
+	for stars := 5; stars >= 1; stars-- {
+		parts = append(parts, fmt.Sprintf("%d★ %d", stars, histogram[stars-1]))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// Stats prints the latest overall rating of every app, store and country
+// with its trend over the -days argument.
+func Stats(config Config, args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	days := flags.Int("days", 7, "trend period in days")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *days < 1 {
+		return fmt.Errorf("Trend period should be at least one day.")
+	}
+	period := time.Duration(*days) * 24 * time.Hour
+
+	for _, app := range config.Apps {
+		summaries, err := dbh.LatestRatings(app.Key())
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(app.Key())
+		if len(summaries) == 0 {
+			fmt.Println("  no ratings recorded yet")
+		}
+		for _, summary := range summaries {
+			location := summary.Store
+			if summary.Country != "" {
+				location += " " + strings.ToUpper(summary.Country)
+			}
+			fmt.Printf("  %s: %s (%d ratings)\n", location, formatRating(roundAverage(summary.Average)), summary.Count)
+
+			if histogram := histogramLabel(summary.Histogram); histogram != "" {
+				fmt.Printf("    %s\n", histogram)
+			}
+
+			trend, err := ratingTrendOver(app.Key(), summary, period)
+			if err != nil {
+				return err
+			}
+			if trend != "" {
+				fmt.Printf("    %s\n", trend)
+			}
+		}
+	}
+
+	return nil
+}
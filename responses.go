@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+)
+
+// MAX_QUOTE_LENGTH bounds the original review quoted by response follow-ups.
+const MAX_QUOTE_LENGTH = 300
+
+// PostDeveloperResponses follows up on reviews answered by a developer since
+// they were posted, linking back to the original review.
+func PostDeveloperResponses(config AppConfig, reviews Reviews) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	attachments := []SlackAttachment{}
+	for i, review := range reviews {
+		if i >= config.ReviewCount {
+			break
+		}
+
+		quote := []rune(review.Message)
+		if len(quote) > MAX_QUOTE_LENGTH {
+			quote = append(quote[:MAX_QUOTE_LENGTH], '…')
+		}
+
+		fields := []SlackAttachmentField{
+			{
+				Title: "In reply to",
+				Value: fmt.Sprintf("%s %s: %s", ratingLabel(review), review.Author, string(quote)),
+			},
+		}
+
+		if !review.DeveloperResponseAt.IsZero() {
+			fields = append(fields, SlackAttachmentField{
+				Title: "RespondedAt",
+				Value: review.DeveloperResponseAt.Format("2006-01-02"),
+				Short: true,
+			})
+		}
+
+		attachments = append(attachments, SlackAttachment{
+			Title:      "Re: " + review.Title,
+			TitleLink:  review.Permalink,
+			AuthorName: "Developer Response",
+			Text:       review.DeveloperResponse,
+			Fallback:   review.DeveloperResponse,
+			Color:      review.Color,
+			Fields:     fields,
+			Footer:     review.Store,
+		})
+	}
+
+	messageText := reviews[0].Store + " Developer Responses:"
+	if config.Name != "" {
+		messageText = config.Name + " " + messageText
+	}
+
+	return SendSlackPayload(config.WebHookUri, SlackPayload{
+		UserName:    config.BotName,
+		IconEmoji:   config.IconEmoji,
+		Text:        messageText,
+		Attachments: attachments,
+	})
+}
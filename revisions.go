@@ -0,0 +1,193 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MAX_DIFF_WORDS bounds the word diff, longer texts are shown in full instead.
+const MAX_DIFF_WORDS = 1000
+
+// ReviewChange is a stored review edited by its author.
+type ReviewChange struct {
+	Old Review
+	New Review
+}
+
+type ReviewChanges []ReviewChange
+
+// ContentHash identifies the user visible content of a review.
+func (r Review) ContentHash() string {
+	hash := sha1.Sum([]byte(strconv.Itoa(r.Rating) + "\x00" + r.Title + "\x00" + r.Message))
+	return hex.EncodeToString(hash[:])
+}
+
+// SaveRevision appends the current content of a stored review to its history.
+func (dbh *DBH) SaveRevision(review Review) error {
+	_, err := dbh.Exec(`INSERT INTO `+REVISION_TABLE_NAME+` (review_id, rating, title, message, content_hash, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		review.Id, review.Rating, review.Title, review.Message, review.ContentHash(), review.UpdatedAt)
+	return err
+}
+
+// CountRatingChange counts an edit moving the rating of a review of app.
+func (dbh *DBH) CountRatingChange(app string, store string, from int, to int) error {
+	upgrades, downgrades := 0, 0
+	switch {
+	case to > from:
+		upgrades = 1
+	case to < from:
+		downgrades = 1
+	default:
+		return nil
+	}
+
+	_, err := dbh.Exec(`INSERT INTO `+RATING_CHANGE_TABLE_NAME+` (app, store, upgrades, downgrades) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (app, store) DO UPDATE SET upgrades = `+RATING_CHANGE_TABLE_NAME+`.upgrades + EXCLUDED.upgrades,
+		downgrades = `+RATING_CHANGE_TABLE_NAME+`.downgrades + EXCLUDED.downgrades`,
+		app, store, upgrades, downgrades)
+	return err
+}
+
+// RatingChanges returns how many edits raised and lowered ratings of app on store.
+func (dbh *DBH) RatingChanges(app string, store string) (upgrades int, downgrades int, err error) {
+	row := dbh.QueryRow(`SELECT COALESCE(SUM(upgrades), 0), COALESCE(SUM(downgrades), 0) FROM `+RATING_CHANGE_TABLE_NAME+` WHERE app = $1 AND store = $2`, app, store)
+	err = row.Scan(&upgrades, &downgrades)
+	return upgrades, downgrades, err
+}
+
+// PostReviewChanges posts edited reviews with their old and new rating and a diff of their text.
+func PostReviewChanges(config AppConfig, changes ReviewChanges) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	attachments := []SlackAttachment{}
+	for i, change := range changes {
+		if i >= config.ReviewCount {
+			break
+		}
+
+		fields := []SlackAttachmentField{
+			{
+				Title: "Rating",
+				Value: fmt.Sprintf("%s → %s", ratingLabel(change.Old), ratingLabel(change.New)),
+				Short: true,
+			},
+			{
+				Title: "UpdatedAt",
+				Value: change.New.UpdatedAt.Format("2006-01-02"),
+				Short: true,
+			},
+		}
+
+		if change.Old.Title != change.New.Title {
+			fields = append(fields, SlackAttachmentField{
+				Title: "Title",
+				Value: WordDiff(change.Old.Title, change.New.Title),
+			})
+		}
+
+		footer := change.New.Store
+		upgrades, downgrades, err := dbh.RatingChanges(change.New.App, change.New.Store)
+		if err != nil {
+			return err
+		}
+		footer += fmt.Sprintf(" · %d rating upgrades, %d downgrades", upgrades, downgrades)
+
+		attachments = append(attachments, SlackAttachment{
+			Title:      change.New.Title,
+			TitleLink:  change.New.Permalink,
+			AuthorName: change.New.Author,
+			Text:       WordDiff(change.Old.Message, change.New.Message),
+			Fallback:   change.New.Message + " " + change.New.Author,
+			Color:      change.New.Color,
+			Fields:     fields,
+			Footer:     footer,
+		})
+	}
+
+	messageText := changes[0].New.Store + " Updated Reviews:"
+	if config.Name != "" {
+		messageText = config.Name + " " + messageText
+	}
+
+	return SendSlackPayload(config.WebHookUri, SlackPayload{
+		UserName:    config.BotName,
+		IconEmoji:   config.IconEmoji,
+		Text:        messageText,
+		Attachments: attachments,
+	})
+}
+
+func ratingLabel(review Review) string {
+	if review.Rate != "" {
+		return review.Rate
+	}
+	return strconv.Itoa(review.Rating)
+}
+
+// WordDiff renders the changes between two texts with slack markup, removed
+// words are struck through and added words are bold.
+func WordDiff(from string, to string) string {
+	if from == to {
+		return to
+	}
+
+	a, b := strings.Fields(from), strings.Fields(to)
+	if len(a) > MAX_DIFF_WORDS || len(b) > MAX_DIFF_WORDS {
+		return fmt.Sprintf("~%s~\n*%s*", from, to)
+	}
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	words := []string{}
+	removed, added := []string{}, []string{}
+	flush := func() {
+		if len(removed) > 0 {
+			words = append(words, "~"+strings.Join(removed, " ")+"~")
+			removed = removed[:0]
+		}
+		if len(added) > 0 {
+			words = append(words, "*"+strings.Join(added, " ")+"*")
+			added = added[:0]
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			words = append(words, a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, b[j])
+			j++
+		default:
+			removed = append(removed, a[i])
+			i++
+		}
+	}
+	flush()
+
+	return strings.Join(words, " ")
+}
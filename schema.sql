@@ -1,9 +1,91 @@
 CREATE TABLE review (
   id SERIAL PRIMARY KEY,
+  app VARCHAR(255) NOT NULL DEFAULT '',
   store VARCHAR(255) NOT NULL,
+  country VARCHAR(8) NOT NULL DEFAULT '',
+  external_id VARCHAR(255) NULL,
   author VARCHAR(255) NULL,
   comment_uri VARCHAR(255) NULL,
-  updated_at DATE NOT NULL
+  updated_at DATE NOT NULL,
+  app_version VARCHAR(255) NOT NULL DEFAULT '',
+  language VARCHAR(32) NOT NULL DEFAULT '',
+  device VARCHAR(255) NOT NULL DEFAULT '',
+  os_version VARCHAR(255) NOT NULL DEFAULT '',
+  rating INT NOT NULL DEFAULT 0,
+  title TEXT NOT NULL DEFAULT '',
+  message TEXT NOT NULL DEFAULT '',
+  content_hash VARCHAR(40) NOT NULL DEFAULT '',
+  response TEXT NOT NULL DEFAULT '',
+  response_at TIMESTAMP WITH TIME ZONE NULL,
+  notified BOOLEAN NOT NULL DEFAULT FALSE
 );
 CREATE INDEX comment_uri_idx on review(comment_uri);
 CREATE INDEX store_idx on review(store);
+CREATE INDEX app_idx on review(app);
+CREATE UNIQUE INDEX external_id_idx on review(store, app, external_id);
+
+CREATE TABLE watermark (
+  app VARCHAR(255) NOT NULL,
+  store VARCHAR(255) NOT NULL,
+  country VARCHAR(32) NOT NULL DEFAULT '',
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (app, store, country)
+);
+
+CREATE TABLE review_revision (
+  id SERIAL PRIMARY KEY,
+  review_id INT NOT NULL REFERENCES review(id) ON DELETE CASCADE,
+  rating INT NOT NULL,
+  title TEXT NOT NULL,
+  message TEXT NOT NULL,
+  content_hash VARCHAR(40) NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX review_revision_review_id_idx on review_revision(review_id);
+
+CREATE TABLE rating_change (
+  app VARCHAR(255) NOT NULL,
+  store VARCHAR(255) NOT NULL,
+  upgrades INT NOT NULL DEFAULT 0,
+  downgrades INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (app, store)
+);
+
+CREATE TABLE digest (
+  app VARCHAR(255) PRIMARY KEY,
+  review_id INT NOT NULL,
+  sent_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE metadata_snapshot (
+  id SERIAL PRIMARY KEY,
+  app VARCHAR(255) NOT NULL,
+  store VARCHAR(255) NOT NULL,
+  country VARCHAR(8) NOT NULL DEFAULT '',
+  version VARCHAR(255) NOT NULL DEFAULT '',
+  release_notes TEXT NOT NULL DEFAULT '',
+  price VARCHAR(255) NOT NULL DEFAULT '',
+  size BIGINT NOT NULL DEFAULT 0,
+  minimum_os_version VARCHAR(255) NOT NULL DEFAULT '',
+  average_rating DOUBLE PRECISION NOT NULL DEFAULT 0,
+  rating_count INT NOT NULL DEFAULT 0,
+  recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX metadata_snapshot_app_idx on metadata_snapshot(app, store, country);
+
+CREATE TABLE rating_snapshot (
+  id SERIAL PRIMARY KEY,
+  app VARCHAR(255) NOT NULL,
+  store VARCHAR(255) NOT NULL,
+  country VARCHAR(8) NOT NULL DEFAULT '',
+  average_rating DOUBLE PRECISION NOT NULL,
+  rating_count INT NOT NULL,
+  stars_1 INT NULL,
+  stars_2 INT NULL,
+  stars_3 INT NULL,
+  stars_4 INT NULL,
+  stars_5 INT NULL,
+  recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX rating_snapshot_app_idx on rating_snapshot(app, store, country, recorded_at);
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ParseError reports a fetched entry which could not be turned into a Review.
+type ParseError struct {
+	Source  string
+	Country string
+	Page    int
+	Entry   int
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	location := e.Source
+	if e.Country != "" {
+		location += " " + e.Country
+	}
+	return fmt.Sprintf("%s page %d entry %d skipped: %v", location, e.Page, e.Entry, e.Err)
+}
+
+// ParseErrors collects the entries skipped during a fetch.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := []string{}
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ReviewSource fetches reviews of one app from a single store.
+type ReviewSource interface {
+	// Name is the store name, reviews fetched by the source carry it as Store.
+	Name() string
+	// Fetch returns reviews updated since the given watermark, newest first.
+	// A zero watermark means nothing has been seen yet. Entries which could
+	// not be parsed are skipped and returned as ParseErrors along with the
+	// remaining reviews.
+	Fetch(since time.Time) (Reviews, error)
+	Capabilities() SourceCapabilities
+}
+
+// SourceCapabilities describes what a ReviewSource is able to provide.
+type SourceCapabilities struct {
+	// Paginated sources walk further pages until the watermark is reached,
+	// others only return their latest reviews and cannot be backfilled.
+	Paginated bool
+	// Countries sources tag reviews with the storefront country, their
+	// watermarks are kept per country as well.
+	Countries bool
+	// DeveloperResponses sources fill developer replies on reviews, new
+	// replies are posted.
+	DeveloperResponses bool
+}
+
+// WatermarkSource is implemented by sources keeping their storefront
+// watermarks under keys of their own rather than review countries.
+type WatermarkSource interface {
+	// Watermarks returns the newest review date by key seen by the last Fetch.
+	Watermarks() map[string]time.Time
+}
+
+// SourceFactory builds a ReviewSource for app, it returns nil when the
+// source is not configured for app.
+type SourceFactory func(app AppConfig) ReviewSource
+
+// SourcesFactory builds any number of ReviewSources for app, for sources
+// configured as a list such as feeds.
+type SourcesFactory func(app AppConfig) []ReviewSource
+
+type registeredSource struct {
+	name    string
+	factory SourcesFactory
+}
+
+var sourceRegistry []registeredSource
+
+// RegisterSource makes a review source available to every run. It is meant
+// to be called from init functions.
+func RegisterSource(name string, factory SourceFactory) {
+	RegisterSources(name, func(app AppConfig) []ReviewSource {
+		if source := factory(app); source != nil {
+			return []ReviewSource{source}
+		}
+		return nil
+	})
+}
+
+// RegisterSources is RegisterSource for factories of several sources.
+func RegisterSources(name string, factory SourcesFactory) {
+	for _, source := range sourceRegistry {
+		if source.name == name {
+			log.Fatalf("review source %s registered twice", name)
+		}
+	}
+	sourceRegistry = append(sourceRegistry, registeredSource{name, factory})
+}
+
+// NewSources returns every registered source configured for app.
+func NewSources(app AppConfig) []ReviewSource {
+	sources := []ReviewSource{}
+	for _, registered := range sourceRegistry {
+		sources = append(sources, registered.factory(app)...)
+	}
+	return sources
+}
+
+// BACKFILL_MAX_PAGES bounds the pages walked by a backfill, which otherwise
+// goes on until the backfill date.
+const BACKFILL_MAX_PAGES = 1000
+
+// backfilling makes sources walk past known reviews and their usual page
+// limit, down to the watermark given to Fetch.
+var backfilling bool
+
+// MaxPages returns the number of pages a source may walk, pages is its limit
+// on regular runs.
+func MaxPages(pages int) int {
+	if backfilling {
+		return BACKFILL_MAX_PAGES
+	}
+	return pages
+}
+
+// ProcessReviews fetches new reviews of app from source, stores them and posts them to slack.
+func ProcessReviews(app AppConfig, source ReviewSource) error {
+	log.Printf("Processing %s reviews of %s ...", source.Name(), app.Key())
+
+	since, err := dbh.Watermark(app.Key(), source.Name(), "")
+	if err != nil {
+		return err
+	}
+
+	reviews, err := source.Fetch(since)
+	if parseErrors, ok := err.(ParseErrors); ok {
+		for _, parseError := range parseErrors {
+			log.Println(parseError)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	for i := range reviews {
+		reviews[i].App = app.Key()
+	}
+
+	fetched := reviews
+	saved, err := SaveReviews(reviews)
+	if err != nil {
+		return err
+	}
+
+	err = saveWatermarks(app, source, fetched)
+	if err != nil {
+		return err
+	}
+
+	// competitor reviews are only summarized by digests
+	if app.Competitor {
+		log.Printf("%s reviews of competitor %s stored", source.Name(), app.Key())
+		return nil
+	}
+
+	err = PostReview(app, saved.New)
+	if err != nil {
+		return err
+	}
+
+	posted := saved.New
+	if len(posted) > app.ReviewCount {
+		posted = posted[:app.ReviewCount]
+	}
+	err = dbh.MarkNotified(posted)
+	if err != nil {
+		return err
+	}
+
+	err = PostReviewChanges(app, saved.Changed)
+	if err != nil {
+		return err
+	}
+
+	if source.Capabilities().DeveloperResponses {
+		err = PostDeveloperResponses(app, saved.Responded)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("%s reviews process finished", source.Name())
+
+	return nil
+}
+
+// saveWatermarks moves the watermarks of source forward to the newest fetched
+// review, per storefront as well when the source tags reviews with countries
+// or keeps watermarks of its own.
+func saveWatermarks(app AppConfig, source ReviewSource, reviews Reviews) error {
+	keyed, ok := source.(WatermarkSource)
+	perCountry := source.Capabilities().Countries && !ok
+
+	watermarks := map[string]time.Time{}
+	if ok {
+		for key, watermark := range keyed.Watermarks() {
+			watermarks[key] = watermark
+		}
+	}
+	for _, review := range reviews {
+		countries := []string{""}
+		if perCountry && review.Country != "" {
+			countries = append(countries, review.Country)
+		}
+		for _, country := range countries {
+			if review.UpdatedAt.After(watermarks[country]) {
+				watermarks[country] = review.UpdatedAt
+			}
+		}
+	}
+
+	for country, watermark := range watermarks {
+		err := dbh.SetWatermark(app.Key(), source.Name(), country, watermark)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TakeUnseen returns the leading reviews of a newest first page which are
+// either new or edited for app, and not older than since. done reports that
+// a known or older review was reached and further pages can be skipped.
+// Known reviews are only skipped while backfilling.
+func TakeUnseen(app string, reviews Reviews, since time.Time) (unseen Reviews, done bool, err error) {
+	unseen = Reviews{}
+	for _, review := range reviews {
+		if review.UpdatedAt.Before(since) {
+			return unseen, true, nil
+		}
+
+		review.App = app
+		stored, err := dbh.FindReview(review)
+		if err != nil {
+			return unseen, true, err
+		}
+		// edited reviews move to the top, they are kept to report the edit
+		// as are reviews with a new developer response
+		if stored != nil {
+			responded := review.DeveloperResponse != "" && review.DeveloperResponse != stored.DeveloperResponse
+			edited := stored.StoredHash != "" && stored.StoredHash != review.ContentHash()
+			if !responded && !edited {
+				// backfills go on to older pages, the gap may be further down
+				if backfilling {
+					continue
+				}
+				return unseen, true, nil
+			}
+		}
+
+		unseen = append(unseen, review)
+	}
+	return unseen, false, nil
+}
+
+// Since returns reviews updated at or after t.
+func (r Reviews) Since(t time.Time) Reviews {
+	reviews := Reviews{}
+	for _, review := range r {
+		if !review.UpdatedAt.Before(t) {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews
+}
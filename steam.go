@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	STEAM_NAME                  = "Steam"
+	STEAM_API_URI               = "https://store.steampowered.com/appreviews/"
+	STEAM_DEFAULT_LANGUAGE      = "all"
+	STEAM_DEFAULT_PURCHASE_TYPE = "all"
+	STEAM_PAGE_SIZE             = 100
+	STEAM_MAX_PAGES             = 10
+	// Steam reviews recommend a game or not, they are stored as the highest
+	// and lowest star ratings so rating changes are still counted.
+	STEAM_RECOMMENDED_RATING     = 5
+	STEAM_NOT_RECOMMENDED_RATING = 1
+)
+
+var steamClient = &http.Client{Timeout: 30 * time.Second}
+
+func init() {
+	RegisterSource(STEAM_NAME, NewSteamSource)
+}
+
+type SteamSource struct {
+	config AppConfig
+}
+
+func NewSteamSource(config AppConfig) ReviewSource {
+	if config.SteamAppId == "" {
+		return nil
+	}
+	return &SteamSource{config}
+}
+
+func (s *SteamSource) Name() string {
+	return STEAM_NAME
+}
+
+func (s *SteamSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true, DeveloperResponses: true}
+}
+
+type steamReviews struct {
+	Success int           `json:"success"`
+	Cursor  string        `json:"cursor"`
+	Reviews []steamReview `json:"reviews"`
+}
+
+type steamReview struct {
+	RecommendationId string `json:"recommendationid"`
+	Author           struct {
+		SteamId string `json:"steamid"`
+		// PersonaName is the profile name, sent by some API responses only.
+		PersonaName     string `json:"personaname"`
+		PlaytimeForever int    `json:"playtime_forever"`
+	} `json:"author"`
+	Language              string `json:"language"`
+	Review                string `json:"review"`
+	TimestampCreated      int64  `json:"timestamp_created"`
+	TimestampUpdated      int64  `json:"timestamp_updated"`
+	VotedUp               bool   `json:"voted_up"`
+	VotesUp               int    `json:"votes_up"`
+	DeveloperResponse     string `json:"developer_response"`
+	TimestampDevResponded int64  `json:"timestamp_dev_responded"`
+}
+
+// Fetch follows the review cursor of the last updated first reviews until a
+// known review or one older than since is reached.
+func (s *SteamSource) Fetch(since time.Time) (Reviews, error) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	cursor := "*"
+	for page := 1; page <= MaxPages(STEAM_MAX_PAGES); page++ {
+		pageReviews, pageErrors, next, err := GetSteamReviews(s.config, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, parseError := range pageErrors {
+			parseError.Page = page
+		}
+		parseErrors = append(parseErrors, pageErrors...)
+
+		unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, unseen...)
+		// the last page returns its own cursor again
+		if done || next == "" || next == cursor {
+			break
+		}
+		cursor = next
+	}
+
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}
+
+// GetSteamReviews returns the page of reviews at cursor from the appreviews
+// API, and the cursor of the next page.
+func GetSteamReviews(config AppConfig, cursor string) (Reviews, ParseErrors, string, error) {
+	query := url.Values{}
+	query.Add("json", "1")
+	// sorted by last update, so edited reviews come back to the first pages
+	query.Add("filter", "updated")
+	query.Add("language", config.SteamLanguage)
+	query.Add("purchase_type", config.SteamPurchaseType)
+	query.Add("review_type", "all")
+	query.Add("num_per_page", strconv.Itoa(STEAM_PAGE_SIZE))
+	query.Add("cursor", cursor)
+	uri := STEAM_API_URI + url.PathEscape(config.SteamAppId) + "?" + query.Encode()
+	log.Println(uri)
+
+	res, err := steamClient.Get(uri)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, "", fmt.Errorf("%s responded %s", uri, res.Status)
+	}
+
+	var list steamReviews
+	if err := json.NewDecoder(res.Body).Decode(&list); err != nil {
+		return nil, nil, "", fmt.Errorf("decoding Steam reviews failed: %v", err)
+	}
+	if list.Success != 1 {
+		return nil, nil, "", fmt.Errorf("%s was not successful", uri)
+	}
+
+	reviews, parseErrors := ParseSteamReviews(config.SteamAppId, list)
+	return reviews, parseErrors, list.Cursor, nil
+}
+
+// ParseSteamReviews maps a page of the appreviews API of appId.
+func ParseSteamReviews(appId string, list steamReviews) (Reviews, ParseErrors) {
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for i, entry := range list.Reviews {
+		review, err := parseSteamReview(appId, entry)
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{
+				Source: STEAM_NAME,
+				Entry:  i,
+				Err:    err,
+			})
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	sort.Sort(reviews)
+	return reviews, parseErrors
+}
+
+func parseSteamReview(appId string, entry steamReview) (Review, error) {
+	if entry.RecommendationId == "" {
+		return Review{}, fmt.Errorf("missing recommendation id")
+	}
+	// edits move timestamp_updated, which equals timestamp_created until then
+	updated := entry.TimestampUpdated
+	if updated == 0 {
+		updated = entry.TimestampCreated
+	}
+	if updated == 0 {
+		return Review{}, fmt.Errorf("missing date of review %s", entry.RecommendationId)
+	}
+
+	rating := STEAM_NOT_RECOMMENDED_RATING
+	if entry.VotedUp {
+		rating = STEAM_RECOMMENDED_RATING
+	}
+
+	author := entry.Author.PersonaName
+	if author == "" {
+		author = entry.Author.SteamId
+	}
+
+	review := Review{
+		ExternalID:   entry.RecommendationId,
+		Author:       author,
+		Store:        STEAM_NAME,
+		Title:        "No title provided",
+		Message:      strings.TrimSpace(entry.Review),
+		Rating:       rating,
+		Rate:         parseSteamRate(entry.VotedUp),
+		Color:        steamColor(entry.VotedUp),
+		UpdatedAt:    time.Unix(updated, 0),
+		Permalink:    fmt.Sprintf("https://steamcommunity.com/profiles/%s/recommended/%s/", entry.Author.SteamId, appId),
+		Language:     entry.Language,
+		HelpfulVotes: entry.VotesUp,
+	}
+
+	if entry.DeveloperResponse != "" {
+		review.DeveloperResponse = entry.DeveloperResponse
+		review.DeveloperResponseAt = time.Unix(entry.TimestampDevResponded, 0)
+	}
+
+	return review, nil
+}
+
+// parseSteamRate renders a recommendation in place of star ratings.
+func parseSteamRate(votedUp bool) string {
+	if votedUp {
+		return ":+1: Recommended"
+	}
+	return ":-1: Not Recommended"
+}
+
+func steamColor(votedUp bool) string {
+	if votedUp {
+		return "good"
+	}
+	return "danger"
+}
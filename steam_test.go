@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseSteamReviews(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/steam_reviews.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var list steamReviews
+	if err := json.Unmarshal(fixture, &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Success != 1 || list.Cursor != "AoJ4tey90tECcbOXSw==" {
+		t.Errorf("page is successful %d with cursor %q", list.Success, list.Cursor)
+	}
+
+	reviews, parseErrors := ParseSteamReviews("1234560", list)
+	if len(parseErrors) != 1 || parseErrors[0].Entry != 2 {
+		t.Errorf("skipped entries are %v, want entry 2", parseErrors)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("%d reviews, want 2", len(reviews))
+	}
+
+	review := reviews[0]
+	if review.ExternalID != "131234567" || review.Author != "PixelPilot" || review.Store != STEAM_NAME ||
+		review.Message != "Great co-op, runs well on the Deck." || review.Language != "english" || review.HelpfulVotes != 12 {
+		t.Errorf("first review is %+v", review)
+	}
+	if review.Rating != STEAM_RECOMMENDED_RATING || review.Rate != parseSteamRate(true) || review.Color != "good" {
+		t.Errorf("first review is rated %d %q", review.Rating, review.Rate)
+	}
+	if !review.UpdatedAt.Equal(time.Unix(1672653600, 0)) {
+		t.Errorf("first review is dated %v, want its update", review.UpdatedAt)
+	}
+	if review.DeveloperResponse != "Thanks for playing!" || !review.DeveloperResponseAt.Equal(time.Unix(1672740000, 0)) {
+		t.Errorf("first review response is %q at %v", review.DeveloperResponse, review.DeveloperResponseAt)
+	}
+	if review.Permalink != "https://steamcommunity.com/profiles/76561198000000001/recommended/1234560/" {
+		t.Errorf("first review permalink is %s", review.Permalink)
+	}
+
+	// authors without a persona name fall back to their steam id
+	review = reviews[1]
+	if review.ExternalID != "131234500" || review.Author != "76561198000000002" ||
+		review.Rating != STEAM_NOT_RECOMMENDED_RATING || review.Rate != parseSteamRate(false) || review.DeveloperResponse != "" {
+		t.Errorf("second review is %+v", review)
+	}
+	if !review.UpdatedAt.Equal(time.Unix(1672444800, 0)) {
+		t.Errorf("second review is dated %v, want its creation", review.UpdatedAt)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+)
+
+const APP_STORE_ALL_STOREFRONTS = "all"
+
+// APP_STORE_STOREFRONTS lists the country codes of every App Store storefront.
+var APP_STORE_STOREFRONTS = []string{
+	"ae", "ag", "ai", "al", "am", "ao", "ar", "at", "au", "az",
+	"ba", "bb", "be", "bf", "bg", "bh", "bj", "bm", "bn", "bo",
+	"br", "bs", "bt", "bw", "by", "bz", "ca", "cd", "cg", "ch",
+	"ci", "cl", "cm", "cn", "co", "cr", "cv", "cy", "cz", "de",
+	"dk", "dm", "do", "dz", "ec", "ee", "eg", "es", "fi", "fj",
+	"fm", "fr", "ga", "gb", "gd", "ge", "gh", "gm", "gr", "gt",
+	"gw", "gy", "hk", "hn", "hr", "hu", "id", "ie", "il", "in",
+	"iq", "is", "it", "jm", "jo", "jp", "ke", "kg", "kh", "kn",
+	"kr", "kw", "ky", "kz", "la", "lb", "lc", "lk", "lr", "lt",
+	"lu", "lv", "ly", "ma", "md", "me", "mg", "mk", "ml", "mm",
+	"mn", "mo", "mr", "ms", "mt", "mu", "mv", "mw", "mx", "my",
+	"mz", "na", "ne", "ng", "ni", "nl", "no", "np", "nr", "nz",
+	"om", "pa", "pe", "pg", "ph", "pk", "pl", "pt", "pw", "py",
+	"qa", "ro", "rs", "ru", "rw", "sa", "sb", "sc", "se", "sg",
+	"si", "sk", "sl", "sn", "sr", "st", "sv", "sz", "tc", "td",
+	"th", "tj", "tm", "tn", "to", "tr", "tt", "tw", "tz", "ua",
+	"ug", "us", "uy", "uz", "vc", "ve", "vg", "vn", "vu", "xk",
+	"ye", "za", "zm", "zw",
+}
+
+// ParseStorefronts splits a comma separated list of country codes,
+// "all" expands to every App Store storefront.
+func ParseStorefronts(locations string) []string {
+	storefronts := []string{}
+	for _, location := range strings.Split(locations, ",") {
+		location = strings.ToLower(strings.TrimSpace(location))
+		if location == APP_STORE_ALL_STOREFRONTS {
+			return APP_STORE_STOREFRONTS
+		}
+		if location != "" {
+			storefronts = append(storefronts, location)
+		}
+	}
+	return storefronts
+}
+
+// CountryFlag returns the flag emoji of a two letters country code.
+func CountryFlag(country string) string {
+	if len(country) != 2 {
+		return ""
+	}
+
+	flag := ""
+	for _, c := range strings.ToUpper(country) {
+		if c < 'A' || c > 'Z' {
+			return ""
+		}
+		flag += string('\U0001F1E6' + c - 'A')
+	}
+	return flag
+}
+
+// CountryLabel renders a country code for notifications, like "🇯🇵 JP".
+func CountryLabel(country string) string {
+	return strings.TrimSpace(CountryFlag(country) + " " + strings.ToUpper(country))
+}
+
+// TERRITORY_COUNTRY_CODES maps the ISO 3166-1 alpha-3 territory codes of App
+// Store Connect to the country codes of storefronts.
+var TERRITORY_COUNTRY_CODES = map[string]string{
+	"ARE": "ae", "ATG": "ag", "AIA": "ai", "ALB": "al", "ARM": "am", "AGO": "ao", "ARG": "ar", "AUT": "at", "AUS": "au", "AZE": "az",
+	"BIH": "ba", "BRB": "bb", "BEL": "be", "BFA": "bf", "BGR": "bg", "BHR": "bh", "BEN": "bj", "BMU": "bm", "BRN": "bn", "BOL": "bo",
+	"BRA": "br", "BHS": "bs", "BTN": "bt", "BWA": "bw", "BLR": "by", "BLZ": "bz", "CAN": "ca", "COD": "cd", "COG": "cg", "CHE": "ch",
+	"CIV": "ci", "CHL": "cl", "CMR": "cm", "CHN": "cn", "COL": "co", "CRI": "cr", "CPV": "cv", "CYP": "cy", "CZE": "cz", "DEU": "de",
+	"DNK": "dk", "DMA": "dm", "DOM": "do", "DZA": "dz", "ECU": "ec", "EST": "ee", "EGY": "eg", "ESP": "es", "FIN": "fi", "FJI": "fj",
+	"FSM": "fm", "FRA": "fr", "GAB": "ga", "GBR": "gb", "GRD": "gd", "GEO": "ge", "GHA": "gh", "GMB": "gm", "GRC": "gr", "GTM": "gt",
+	"GNB": "gw", "GUY": "gy", "HKG": "hk", "HND": "hn", "HRV": "hr", "HUN": "hu", "IDN": "id", "IRL": "ie", "ISR": "il", "IND": "in",
+	"IRQ": "iq", "ISL": "is", "ITA": "it", "JAM": "jm", "JOR": "jo", "JPN": "jp", "KEN": "ke", "KGZ": "kg", "KHM": "kh", "KNA": "kn",
+	"KOR": "kr", "KWT": "kw", "CYM": "ky", "KAZ": "kz", "LAO": "la", "LBN": "lb", "LCA": "lc", "LKA": "lk", "LBR": "lr", "LTU": "lt",
+	"LUX": "lu", "LVA": "lv", "LBY": "ly", "MAR": "ma", "MDA": "md", "MNE": "me", "MDG": "mg", "MKD": "mk", "MLI": "ml", "MMR": "mm",
+	"MNG": "mn", "MAC": "mo", "MRT": "mr", "MSR": "ms", "MLT": "mt", "MUS": "mu", "MDV": "mv", "MWI": "mw", "MEX": "mx", "MYS": "my",
+	"MOZ": "mz", "NAM": "na", "NER": "ne", "NGA": "ng", "NIC": "ni", "NLD": "nl", "NOR": "no", "NPL": "np", "NRU": "nr", "NZL": "nz",
+	"OMN": "om", "PAN": "pa", "PER": "pe", "PNG": "pg", "PHL": "ph", "PAK": "pk", "POL": "pl", "PRT": "pt", "PLW": "pw", "PRY": "py",
+	"QAT": "qa", "ROU": "ro", "SRB": "rs", "RUS": "ru", "RWA": "rw", "SAU": "sa", "SLB": "sb", "SYC": "sc", "SWE": "se", "SGP": "sg",
+	"SVN": "si", "SVK": "sk", "SLE": "sl", "SEN": "sn", "SUR": "sr", "STP": "st", "SLV": "sv", "SWZ": "sz", "TCA": "tc", "TCD": "td",
+	"THA": "th", "TJK": "tj", "TKM": "tm", "TUN": "tn", "TON": "to", "TUR": "tr", "TTO": "tt", "TWN": "tw", "TZA": "tz", "UKR": "ua",
+	"UGA": "ug", "USA": "us", "URY": "uy", "UZB": "uz", "VCT": "vc", "VEN": "ve", "VGB": "vg", "VNM": "vn", "VUT": "vu", "XKS": "xk",
+	"YEM": "ye", "ZAF": "za", "ZMB": "zm", "ZWE": "zw",
+}
+
+// TerritoryCountry returns the storefront country code of a territory,
+// unknown territories are kept lowercased.
+func TerritoryCountry(territory string) string {
+	if country, ok := TERRITORY_COUNTRY_CODES[strings.ToUpper(territory)]; ok {
+		return country
+	}
+	return strings.ToLower(territory)
+}
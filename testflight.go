@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	TESTFLIGHT_NAME      = "TestFlight"
+	TESTFLIGHT_PAGE_SIZE = 50
+	TESTFLIGHT_MAX_PAGES = 10
+	// TESTFLIGHT_COLOR tells beta feedback apart from store reviews in slack.
+	TESTFLIGHT_COLOR = "#0D96F6"
+	// TESTFLIGHT_FEEDBACK_URI is the App Store Connect page of a screenshot
+	// feedback, by app id and submission id.
+	TESTFLIGHT_FEEDBACK_URI = "https://appstoreconnect.apple.com/apps/%s/testflight/screenshots/%s"
+)
+
+func init() {
+	RegisterSource(TESTFLIGHT_NAME, NewTestFlightSource)
+}
+
+// TestFlightSource fetches screenshot feedback sent by beta testers through
+// the App Store Connect API.
+type TestFlightSource struct {
+	config AppConfig
+	client *AppStoreConnectClient
+}
+
+func NewTestFlightSource(config AppConfig) ReviewSource {
+	if config.AppStoreAppId == "" || !config.TestFlightFeedback {
+		return nil
+	}
+
+	client, err := NewAppStoreConnectClient(config)
+	if err != nil {
+		log.Printf("App Store Connect key of %s: %v", config.Key(), err)
+		return nil
+	}
+	if client == nil {
+		log.Printf("TestFlight feedback of %s requires an App Store Connect key", config.Key())
+		return nil
+	}
+
+	return &TestFlightSource{config, client}
+}
+
+func (s *TestFlightSource) Name() string {
+	return TESTFLIGHT_NAME
+}
+
+func (s *TestFlightSource) Capabilities() SourceCapabilities {
+	return SourceCapabilities{Paginated: true}
+}
+
+type testFlightSubmissions struct {
+	Data []struct {
+		Id         string `json:"id"`
+		Attributes struct {
+			CreatedDate time.Time `json:"createdDate"`
+			Comment     string    `json:"comment"`
+			Email       string    `json:"email"`
+			DeviceModel string    `json:"deviceModel"`
+			OSVersion   string    `json:"osVersion"`
+			Locale      string    `json:"locale"`
+			Screenshots []struct {
+				URL string `json:"url"`
+			} `json:"screenshots"`
+		} `json:"attributes"`
+		Relationships struct {
+			Build struct {
+				Data *struct {
+					Id string `json:"id"`
+				} `json:"data"`
+			} `json:"build"`
+		} `json:"relationships"`
+	} `json:"data"`
+	Included []struct {
+		Type       string `json:"type"`
+		Id         string `json:"id"`
+		Attributes struct {
+			Version string `json:"version"`
+		} `json:"attributes"`
+	} `json:"included"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+// Fetch follows links.next of the newest first feedback until a known
+// submission or one older than since is reached.
+func (s *TestFlightSource) Fetch(since time.Time) (Reviews, error) {
+	query := url.Values{}
+	query.Add("sort", "-createdDate")
+	query.Add("include", "build")
+	query.Add("fields[builds]", "version")
+	query.Add("limit", fmt.Sprint(TESTFLIGHT_PAGE_SIZE))
+	next := fmt.Sprintf("/v1/apps/%s/betaFeedbackScreenshotSubmissions?%s", url.PathEscape(s.config.AppStoreAppId), query.Encode())
+
+	reviews := Reviews{}
+	parseErrors := ParseErrors{}
+	for page := 1; page <= MaxPages(TESTFLIGHT_MAX_PAGES) && next != ""; page++ {
+		var list testFlightSubmissions
+		if err := s.client.Get(next, &list); err != nil {
+			return nil, err
+		}
+		next = list.Links.Next
+
+		builds := map[string]string{}
+		for _, included := range list.Included {
+			if included.Type == "builds" {
+				builds[included.Id] = included.Attributes.Version
+			}
+		}
+
+		pageReviews := Reviews{}
+		for i, entry := range list.Data {
+			attributes := entry.Attributes
+			if attributes.CreatedDate.IsZero() {
+				parseErrors = append(parseErrors, &ParseError{
+					Source: TESTFLIGHT_NAME,
+					Page:   page,
+					Entry:  i,
+					Err:    fmt.Errorf("missing date of feedback %s", entry.Id),
+				})
+				continue
+			}
+
+			build := ""
+			if data := entry.Relationships.Build.Data; data != nil {
+				build = builds[data.Id]
+			}
+
+			title := "Beta feedback"
+			if build != "" {
+				title += " · build " + build
+			}
+
+			author := attributes.Email
+			if author == "" {
+				author = "Beta tester"
+			}
+
+			// screenshot urls are signed and expire after a while, the
+			// feedback page in App Store Connect keeps showing them
+			screenshots := []string{}
+			for _, screenshot := range attributes.Screenshots {
+				if screenshot.URL != "" {
+					screenshots = append(screenshots, screenshot.URL)
+				}
+			}
+
+			pageReviews = append(pageReviews, Review{
+				ExternalID:  entry.Id,
+				Author:      author,
+				Store:       TESTFLIGHT_NAME,
+				Title:       title,
+				Message:     strings.TrimSpace(attributes.Comment),
+				UpdatedAt:   attributes.CreatedDate,
+				Permalink:   fmt.Sprintf(TESTFLIGHT_FEEDBACK_URI, url.PathEscape(s.config.AppStoreAppId), url.PathEscape(entry.Id)),
+				Color:       TESTFLIGHT_COLOR,
+				AppVersion:  build,
+				Language:    attributes.Locale,
+				Device:      attributes.DeviceModel,
+				OSVersion:   attributes.OSVersion,
+				Screenshots: screenshots,
+			})
+		}
+
+		unseen, done, err := TakeUnseen(s.config.Key(), pageReviews, since)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, unseen...)
+		if done {
+			break
+		}
+	}
+
+	sort.Sort(reviews)
+	if len(parseErrors) > 0 {
+		return reviews, parseErrors
+	}
+	return reviews, nil
+}